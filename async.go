@@ -0,0 +1,109 @@
+package gorequest
+
+import (
+	"context"
+	"sync"
+)
+
+// Future is the pending result of EndAsync.
+type Future struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	agent  *SuperAgent
+
+	mu        sync.Mutex
+	callbacks []func(response Response, body []byte, errs []error)
+	resp      Response
+	body      []byte
+	errs      []error
+}
+
+// EndAsync sends the request in a new goroutine and returns at once, use the Future to wait for the
+// result, cancel the request or register callbacks. The request is sent by a Clone of the SuperAgent,
+// so the SuperAgent can be used for another request right away.
+//
+//	users := gorequest.New().Get("https://example.com/users").EndAsync()
+//	orders := gorequest.New().Get("https://example.com/orders").EndAsync()
+//	orders.OnComplete(func(resp gorequest.Response, body []byte, errs []error) {
+//	  log.Println("orders done")
+//	})
+//	_, usersBody, errs := users.Wait()
+//	_, ordersBody, errs := orders.Wait()
+func (s *SuperAgent) EndAsync() *Future {
+	agent := s.Clone()
+	// the request is sent by the clone, so it reads the readers of the body
+	agent.rawReader = s.rawReader
+	copy(agent.FileData, s.FileData)
+	parent := s.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	agent.ctx = ctx
+
+	f := &Future{
+		done:   make(chan struct{}),
+		cancel: cancel,
+		agent:  agent,
+	}
+	go f.run()
+	return f
+}
+
+func (f *Future) run() {
+	defer f.cancel()
+	resp, body, errs := f.agent.EndBytes()
+
+	f.mu.Lock()
+	f.resp, f.body, f.errs = resp, body, errs
+	callbacks := f.callbacks
+	f.callbacks = nil
+	close(f.done)
+	f.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(resp, body, errs)
+	}
+}
+
+// Wait waits for the request to complete and returns its result, like EndBytes.
+func (f *Future) Wait() (Response, []byte, []error) {
+	<-f.done
+	return f.resp, f.body, f.errs
+}
+
+// Done returns a channel which is closed when the request is complete.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Cancel cancels the request through its context, it does nothing once the request is complete.
+func (f *Future) Cancel() {
+	f.cancel()
+}
+
+// OnComplete registers a callback run with the result once the request is complete, callbacks run
+// in the goroutine of the request in the order they are registered. It runs at once when the
+// request is already complete.
+func (f *Future) OnComplete(callback func(response Response, body []byte, errs []error)) *Future {
+	if callback == nil {
+		return f
+	}
+	f.mu.Lock()
+	select {
+	case <-f.done:
+		f.mu.Unlock()
+		callback(f.resp, f.body, f.errs)
+		return f
+	default:
+	}
+	f.callbacks = append(f.callbacks, callback)
+	f.mu.Unlock()
+	return f
+}
+
+// Stats returns the Stats of the request, it waits for the request to complete.
+func (f *Future) Stats() Stats {
+	<-f.done
+	return f.agent.Stats
+}
@@ -1,5 +1,14 @@
 package gorequest
 
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
 type basicAuth struct {
 	Username string
 	Password string
@@ -16,3 +25,172 @@ func (s *SuperAgent) SetBasicAuth(username string, password string) *SuperAgent
 	s.BasicAuth = basicAuth{username, password}
 	return s
 }
+
+// Authenticator adds credentials to requests, it's applied by MakeRequest on every attempt.
+// It must be safe for concurrent use as clones share it.
+type Authenticator interface {
+	Apply(req *http.Request) error
+}
+
+// ChallengeAuthenticator is an Authenticator which can renew its credentials when the server
+// answers 401 Unauthorized. Challenge returns true when the request should be replayed, the
+// replayed request gets a new body from GetBody and Apply is called again. A request is replayed once.
+type ChallengeAuthenticator interface {
+	Authenticator
+	Challenge(req *http.Request, resp *http.Response) (bool, error)
+}
+
+// SetAuthenticator sets the Authenticator of the requests, it's shared by the clones of the SuperAgent.
+// Example. To send a bearer token refreshed before it expires
+//
+//	auth := gorequest.NewBearerAuth(gorequest.TokenSourceFunc(
+//	  func(ctx context.Context) (*gorequest.Token, error) {
+//	    return fetchToken(ctx)
+//	  }))
+//	gorequest.New().
+//	  SetAuthenticator(auth).
+//	  Get("https://example.com/api").
+//	  End()
+func (s *SuperAgent) SetAuthenticator(auth Authenticator) *SuperAgent {
+	s.authenticator = auth
+	return s
+}
+
+// authMiddleware replays a request answered with 401 Unauthorized when the authenticator renewed
+// its credentials.
+func (s *SuperAgent) authMiddleware(next Handler) Handler {
+	auth, ok := s.authenticator.(ChallengeAuthenticator)
+	if !ok {
+		return next
+	}
+	return func(req *http.Request) (*http.Response, error) {
+		resp, err := next(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, nil
+		}
+		replay, err := auth.Challenge(req, resp)
+		if err != nil {
+			discardBody(resp)
+			return nil, err
+		}
+		if !replay {
+			return resp, nil
+		}
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			retry.Body = body
+		}
+		if err := auth.Apply(retry); err != nil {
+			return resp, nil
+		}
+		discardBody(resp)
+		return next(retry)
+	}
+}
+
+// Token is an access token.
+type Token struct {
+	AccessToken string
+	// TokenType is the authorization scheme, Bearer when empty.
+	TokenType string
+	// RefreshToken is used by OAuth2 to get a new access token, it may be empty.
+	RefreshToken string
+	// Expiry is when the token expires, the zero value means it doesn't expire.
+	Expiry time.Time
+}
+
+// valid reports whether the token can be used for delta more.
+func (t *Token) valid(delta time.Duration) bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Add(delta).Before(t.Expiry))
+}
+
+// TokenSource returns tokens, see NewBearerAuth.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenInvalidator may be implemented by a TokenSource caching its tokens, BearerAuth calls Invalidate
+// with the token rejected by the server so the next call to Token returns a new one.
+type TokenInvalidator interface {
+	Invalidate(token *Token)
+}
+
+// TokenSourceFunc is a func used as a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return f(ctx)
+}
+
+// defaultExpiryDelta is how long before its expiry a token is renewed.
+const defaultExpiryDelta = 10 * time.Second
+
+// BearerAuth is an Authenticator sending the token of a TokenSource in the Authorization header.
+// The token is cached until ExpiryDelta before it expires, and only one goroutine gets a new token
+// at a time. On 401 Unauthorized the token is renewed and the request replayed once.
+type BearerAuth struct {
+	source TokenSource
+	// ExpiryDelta is how long before its expiry the token is renewed, 10 seconds by default.
+	ExpiryDelta time.Duration
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewBearerAuth returns a BearerAuth getting its tokens from source.
+func NewBearerAuth(source TokenSource) *BearerAuth {
+	return &BearerAuth{source: source, ExpiryDelta: defaultExpiryDelta}
+}
+
+// Token returns the cached token, or a new one from the TokenSource when it expires soon.
+func (b *BearerAuth) Token(ctx context.Context) (*Token, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.token.valid(b.ExpiryDelta) {
+		return b.token, nil
+	}
+	token, err := b.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.AccessToken == "" {
+		return nil, errors.New("token source returned an empty token")
+	}
+	b.token = token
+	return token, nil
+}
+
+func (b *BearerAuth) Apply(req *http.Request) error {
+	token, err := b.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	tokenType := token.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	req.Header.Set("Authorization", tokenType+" "+token.AccessToken)
+	return nil
+}
+
+// Challenge drops the cached token when it's the one the server rejected, so Apply gets a new one.
+// The token is invalidated in the TokenSource too when it implements TokenInvalidator.
+func (b *BearerAuth) Challenge(req *http.Request, _ *http.Response) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.token != nil && strings.HasSuffix(req.Header.Get("Authorization"), " "+b.token.AccessToken) {
+		if invalidator, ok := b.source.(TokenInvalidator); ok {
+			invalidator.Invalidate(b.token)
+		}
+		b.token = nil
+	}
+	return true, nil
+}
@@ -0,0 +1,160 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errBatchCancelled is the cause of the cancellation of a fail-fast batch after a failed request.
+var errBatchCancelled = errors.New("batch cancelled after a failed request")
+
+// Batch sends many requests with bounded concurrency, see NewBatch.
+type Batch struct {
+	concurrency int
+	failFast    bool
+	agents      []*SuperAgent
+
+	mu     sync.Mutex
+	cancel context.CancelCauseFunc
+}
+
+// BatchResult is the outcome of one request of a Batch.
+type BatchResult struct {
+	// Index is the position of the request in the batch.
+	Index    int
+	Response Response
+	Body     []byte
+	Errs     []error
+	Stats    Stats
+}
+
+// NewBatch returns a Batch sending at most concurrency requests at once, concurrency less than 1 means 1.
+// Requests are added as prepared SuperAgents with Add, or as a template and per item changes with AddFrom.
+// By default every request is sent and the errors are collected, FailFast cancels the batch on the
+// first failed request instead.
+//
+//	results := gorequest.NewBatch(8).
+//	  AddFrom(gorequest.New().Get("https://example.com/items"),
+//	    func(s *gorequest.SuperAgent) { s.Param("id", "1") },
+//	    func(s *gorequest.SuperAgent) { s.Param("id", "2") },
+//	  ).
+//	  Run(ctx)
+//	for _, result := range results {
+//	  if len(result.Errs) != 0 {
+//	    log.Println(result.Index, result.Errs)
+//	  }
+//	}
+func NewBatch(concurrency int) *Batch {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Batch{concurrency: concurrency}
+}
+
+// Add adds prepared requests to the batch, each SuperAgent must only be used by the batch.
+func (b *Batch) Add(agents ...*SuperAgent) *Batch {
+	for _, agent := range agents {
+		if agent != nil {
+			b.agents = append(b.agents, agent)
+		}
+	}
+	return b
+}
+
+// AddFrom adds a request per mutator, each one is a Clone of template changed by its mutator.
+func (b *Batch) AddFrom(template *SuperAgent, mutators ...func(*SuperAgent)) *Batch {
+	for _, mutate := range mutators {
+		agent := template.Clone()
+		if mutate != nil {
+			mutate(agent)
+		}
+		b.agents = append(b.agents, agent)
+	}
+	return b
+}
+
+// FailFast cancels the requests in flight and skips the requests not sent yet once a request fails,
+// including a status not expected with ExpectStatus. Skipped requests get the cancellation error.
+func (b *Batch) FailFast(enable bool) *Batch {
+	b.failFast = enable
+	return b
+}
+
+// Cancel cancels a running batch, the requests in flight are cancelled through their contexts
+// and the requests not sent yet are skipped.
+func (b *Batch) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		b.cancel(context.Canceled)
+	}
+}
+
+// Run sends the requests and returns their results in the order they were added.
+// The batch is cancelled when ctx is done, each request keeps the context given to its Context too.
+func (b *Batch) Run(ctx context.Context) []BatchResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	b.mu.Lock()
+	b.cancel = cancel
+	b.mu.Unlock()
+
+	results := make([]BatchResult, len(b.agents))
+	sem := make(chan struct{}, b.concurrency)
+	var wg sync.WaitGroup
+	for i, agent := range b.agents {
+		acquired := false
+		select {
+		case sem <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			// the slot may be taken when the batch is cancelled at the same time
+			if acquired {
+				<-sem
+			}
+			results[i] = BatchResult{Index: i, Errs: []error{agent.newError(PhaseRequest, context.Cause(ctx))}}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, agent *SuperAgent) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = b.send(ctx, i, agent)
+			if b.failFast && len(results[i].Errs) != 0 {
+				cancel(errBatchCancelled)
+			}
+		}(i, agent)
+	}
+	wg.Wait()
+	return results
+}
+
+func (b *Batch) send(ctx context.Context, i int, agent *SuperAgent) BatchResult {
+	parent := agent.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	reqCtx, cancel := context.WithCancelCause(parent)
+	defer cancel(nil)
+	stop := context.AfterFunc(ctx, func() { cancel(context.Cause(ctx)) })
+	defer stop()
+	agent.ctx = reqCtx
+
+	resp, body, errs := agent.EndBytes()
+	return BatchResult{
+		Index:    i,
+		Response: resp,
+		Body:     body,
+		Errs:     errs,
+		Stats:    agent.Stats,
+	}
+}
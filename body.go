@@ -0,0 +1,231 @@
+package gorequest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	errBodyNotRewindable = errors.New("body reader has already been read and can't be rewound")
+	errBodyShared        = errors.New("body reader is read by the SuperAgent it was given to, " +
+		"a clone can only send a file or an io.ReaderAt")
+)
+
+// readerSource is a request body or a multipart file which is read when the request is sent
+// instead of being held in memory.
+type readerSource struct {
+	reader   io.Reader
+	path     string
+	size     int64 // -1 when unknown
+	offset   int64 // start position of a seekable reader
+	seekable bool
+	borrowed bool // the source of a clone, the reader can't be read
+
+	mu     sync.Mutex // guards opened and the seeks of the reader
+	opened bool
+}
+
+func newReaderSource(r io.Reader, size int64) *readerSource {
+	src := &readerSource{reader: r, size: size}
+	if seeker, ok := r.(io.Seeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			src.offset = offset
+			src.seekable = true
+		}
+	}
+	if src.size < 0 {
+		src.size = readerLen(r, src.offset, src.seekable)
+	}
+	return src
+}
+
+func newFileSource(path string) (*readerSource, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &os.PathError{Op: "read", Path: path, Err: errors.New("is a directory")}
+	}
+	return &readerSource{path: path, size: info.Size()}, nil
+}
+
+// readerLen returns the remaining length of r, or -1 when it can't be determined.
+func readerLen(r io.Reader, offset int64, seekable bool) int64 {
+	if l, ok := r.(interface{ Len() int }); ok {
+		return int64(l.Len())
+	}
+	if !seekable {
+		return -1
+	}
+	seeker := r.(io.Seeker)
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+	if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+		return -1
+	}
+	return end - offset
+}
+
+func (r *readerSource) rewindable() bool {
+	return r.path != "" || r.seekable
+}
+
+// readerAt returns the reader as an io.ReaderAt when its content can be read by sections,
+// the sections are independent so the source can be sent by several requests at once.
+func (r *readerSource) readerAt() (io.ReaderAt, bool) {
+	ra, ok := r.reader.(io.ReaderAt)
+	return ra, ok && r.seekable && r.size >= 0
+}
+
+// clone returns the source of a clone of the SuperAgent. Files and io.ReaderAt readers are shared,
+// the other readers are borrowed: they stay read by the original SuperAgent only.
+func (r *readerSource) clone() *readerSource {
+	if r == nil || r.path != "" {
+		return r
+	}
+	if _, ok := r.readerAt(); ok {
+		return r
+	}
+	return &readerSource{reader: r.reader, size: r.size, offset: r.offset, seekable: r.seekable, borrowed: true}
+}
+
+// open returns the content from the start, files are opened again and seekable readers are rewound.
+func (r *readerSource) open() (io.ReadCloser, error) {
+	if r.path != "" {
+		return os.Open(r.path)
+	}
+	if ra, ok := r.readerAt(); ok {
+		return io.NopCloser(io.NewSectionReader(ra, r.offset, r.size)), nil
+	}
+	if r.borrowed {
+		return nil, errBodyShared
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seekable {
+		if _, err := r.reader.(io.Seeker).Seek(r.offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return io.NopCloser(r.reader), nil
+	}
+	if r.opened {
+		return nil, errBodyNotRewindable
+	}
+	r.opened = true
+	return io.NopCloser(r.reader), nil
+}
+
+// bodyPart is either in memory data or a readerSource.
+type bodyPart struct {
+	data   []byte
+	source *readerSource
+}
+
+// streamBody is a request body made of parts which are read on demand.
+type streamBody struct {
+	io.ReadCloser
+	size        int64 // -1 when unknown
+	contentType string
+	getBody     func() (io.ReadCloser, error) // nil when the body can't be rewound
+}
+
+func newStreamBody(parts []bodyPart, contentType string) (*streamBody, error) {
+	var (
+		size       int64
+		rewindable = true
+	)
+	for _, part := range parts {
+		if part.source == nil {
+			size += int64(len(part.data))
+			continue
+		}
+		if size >= 0 && part.source.size >= 0 {
+			size += part.source.size
+		} else {
+			size = -1
+		}
+		rewindable = rewindable && part.source.rewindable()
+	}
+
+	open := func() (io.ReadCloser, error) {
+		readers := make([]io.Reader, 0, len(parts))
+		closers := make([]io.Closer, 0, len(parts))
+		for _, part := range parts {
+			if part.source == nil {
+				readers = append(readers, bytes.NewReader(part.data))
+				continue
+			}
+			rc, err := part.source.open()
+			if err != nil {
+				closeAll(closers)
+				return nil, err
+			}
+			readers = append(readers, rc)
+			closers = append(closers, rc)
+		}
+		return &multiReadCloser{Reader: io.MultiReader(readers...), closers: closers}, nil
+	}
+
+	body, err := open()
+	if err != nil {
+		return nil, err
+	}
+	b := &streamBody{
+		ReadCloser:  body,
+		size:        size,
+		contentType: contentType,
+	}
+	if rewindable {
+		b.getBody = open
+	}
+	return b, nil
+}
+
+type multiReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiReadCloser) Close() error {
+	return closeAll(m.closers)
+}
+
+func closeAll(closers []io.Closer) error {
+	var err error
+	for _, c := range closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// SendReader sends the content of r as a raw request body without reading it into memory.
+// size is the length of the content, -1 means unknown, and it's detected for readers
+// such as *bytes.Reader, *strings.Reader or *os.File. The Content-Length header is only
+// set when the size is known.
+// If r is an io.Seeker it's rewound for retries and redirects, otherwise it can only be sent once.
+// Clones of the SuperAgent can send r too when it's an io.ReaderAt, such as *bytes.Reader, *strings.Reader
+// or *os.File, each one reads its own section; other readers are only sent by this SuperAgent.
+//
+//	f, _ := os.Open("./backup.tar")
+//	defer f.Close()
+//	gorequest.New().
+//	  Put("http://example.com/upload").
+//	  SendReader(f, -1).
+//	  End()
+func (s *SuperAgent) SendReader(r io.Reader, size int64) *SuperAgent {
+	if r == nil {
+		s.appendError(PhaseBuild, errors.New("sendReader func: nil reader"))
+		return s
+	}
+	s.RawBytes = nil
+	s.rawReader = newReaderSource(r, size)
+	return s
+}
@@ -0,0 +1,281 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped, when a request is rejected because the circuit breaker
+// of its host is open, check it with errors.Is.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the circuit breaker of a host.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through and counts the failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request until the cool-down period is over.
+	CircuitOpen
+	// CircuitHalfOpen lets a few probe requests through to decide whether the host is back.
+	CircuitHalfOpen
+)
+
+func (c CircuitState) String() string {
+	switch c {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(c))
+	}
+}
+
+// CircuitBreakerConfig configures CircuitBreaker, zero values use the defaults.
+type CircuitBreakerConfig struct {
+	// FailureRate opens the circuit when the rate of failed requests in the window reaches it,
+	// between 0 and 1, 0.5 by default.
+	FailureRate float64
+	// MinRequests is the number of requests in the window before the failure rate is checked, 10 by default.
+	MinRequests int
+	// Window is the period over which the requests are counted, 1 minute by default.
+	Window time.Duration
+	// CoolDown is how long the circuit stays open before letting probe requests through, 30 seconds by default.
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of probe requests which must succeed to close the circuit, 1 by default.
+	HalfOpenRequests int
+	// IsFailure reports whether a request failed, by default transport errors and 5xx responses are failures.
+	// It's not called for the requests rejected before being sent by MaxConcurrent or RateLimit, or
+	// cancelled by the caller, they're neither successes nor failures.
+	IsFailure func(resp *http.Response, err error) bool
+	// OnStateChange is called when the circuit of a host changes state.
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+func (c CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if c.FailureRate <= 0 || c.FailureRate > 1 {
+		c.FailureRate = 0.5
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = 10
+	}
+	if c.Window <= 0 {
+		c.Window = time.Minute
+	}
+	if c.CoolDown <= 0 {
+		c.CoolDown = 30 * time.Second
+	}
+	if c.HalfOpenRequests <= 0 {
+		c.HalfOpenRequests = 1
+	}
+	if c.IsFailure == nil {
+		c.IsFailure = isFailure
+	}
+	return c
+}
+
+func isFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// isNotSent reports whether err is a rejection of the client before the request reached the host,
+// or a cancellation by the caller.
+func isNotSent(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, ErrConcurrencyLimit) || errors.Is(err, ErrRateLimitWait)
+}
+
+// circuitBreaker holds the circuits of all hosts, it's shared by the clones of a SuperAgent.
+type circuitBreaker struct {
+	cfg      CircuitBreakerConfig
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+	changes  []stateChange // state changes to report once mu is unlocked
+}
+
+type stateChange struct {
+	host     string
+	from, to CircuitState
+}
+
+type circuit struct {
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int // requests let through while half-open
+	successes   int // successful probes
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		cfg:      cfg.withDefaults(),
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+// CircuitBreaker stops sending requests to a host which keeps failing: once the failure rate of
+// the host reaches cfg.FailureRate the circuit opens and requests fail fast with ErrCircuitOpen,
+// without being retried. After cfg.CoolDown probe requests are let through, and the circuit closes
+// again when they succeed.
+// The state of every host is shared by the clones of the SuperAgent made after this call.
+// Example. To open the circuit when half of at least 20 requests in a minute fail
+//
+//	base := gorequest.New().CircuitBreaker(gorequest.CircuitBreakerConfig{
+//	  FailureRate: 0.5,
+//	  MinRequests: 20,
+//	  CoolDown:    10 * time.Second,
+//	  OnStateChange: func(host string, from, to gorequest.CircuitState) {
+//	    log.Printf("circuit of %s: %s -> %s", host, from, to)
+//	  },
+//	})
+//	resp, body, errs := base.Clone().Get("https://httpbin.org/get").End()
+func (s *SuperAgent) CircuitBreaker(cfg CircuitBreakerConfig) *SuperAgent {
+	s.breaker = newCircuitBreaker(cfg)
+	return s
+}
+
+// CircuitState returns the state of the circuit breaker for host, CircuitClosed without CircuitBreaker.
+func (s *SuperAgent) CircuitState(host string) CircuitState {
+	if s.breaker == nil {
+		return CircuitClosed
+	}
+	b := s.breaker
+	b.mu.Lock()
+	defer b.unlock()
+	c, ok := b.circuits[host]
+	if !ok {
+		return CircuitClosed
+	}
+	b.refresh(host, c, b.now())
+	return c.state
+}
+
+func (b *circuitBreaker) middleware(next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		host := req.URL.Host
+		if err := b.allow(host); err != nil {
+			return nil, err
+		}
+		resp, err := next(req)
+		if isNotSent(err) {
+			b.release(host)
+			return resp, err
+		}
+		b.record(host, b.cfg.IsFailure(resp, err))
+		return resp, err
+	}
+}
+
+// allow returns an error when the request to host must be rejected.
+func (b *circuitBreaker) allow(host string) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	now := b.now()
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{windowStart: now}
+		b.circuits[host] = c
+	}
+	b.refresh(host, c, now)
+
+	switch c.state {
+	case CircuitOpen:
+		return fmt.Errorf("%w for host %s", ErrCircuitOpen, host)
+	case CircuitHalfOpen:
+		if c.probes >= b.cfg.HalfOpenRequests {
+			return fmt.Errorf("%w for host %s", ErrCircuitOpen, host)
+		}
+		c.probes++
+	}
+	return nil
+}
+
+func (b *circuitBreaker) record(host string, failed bool) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	c := b.circuits[host]
+	now := b.now()
+	switch c.state {
+	case CircuitHalfOpen:
+		if failed {
+			b.setState(host, c, CircuitOpen, now)
+			return
+		}
+		c.successes++
+		if c.successes >= b.cfg.HalfOpenRequests {
+			b.setState(host, c, CircuitClosed, now)
+		}
+	case CircuitClosed:
+		c.requests++
+		if failed {
+			c.failures++
+		}
+		if c.requests >= b.cfg.MinRequests && float64(c.failures)/float64(c.requests) >= b.cfg.FailureRate {
+			b.setState(host, c, CircuitOpen, now)
+		}
+	}
+}
+
+// release gives back the probe slot of a half-open circuit taken by a request which wasn't sent.
+func (b *circuitBreaker) release(host string) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if c := b.circuits[host]; c.state == CircuitHalfOpen && c.probes > 0 {
+		c.probes--
+	}
+}
+
+// refresh starts a new window and moves an open circuit to half-open after the cool-down.
+func (b *circuitBreaker) refresh(host string, c *circuit, now time.Time) {
+	switch c.state {
+	case CircuitClosed:
+		if now.Sub(c.windowStart) >= b.cfg.Window {
+			c.windowStart = now
+			c.requests = 0
+			c.failures = 0
+		}
+	case CircuitOpen:
+		if now.Sub(c.openedAt) >= b.cfg.CoolDown {
+			b.setState(host, c, CircuitHalfOpen, now)
+		}
+	}
+}
+
+func (b *circuitBreaker) setState(host string, c *circuit, state CircuitState, now time.Time) {
+	from := c.state
+	c.state = state
+	c.windowStart = now
+	c.requests = 0
+	c.failures = 0
+	c.probes = 0
+	c.successes = 0
+	if state == CircuitOpen {
+		c.openedAt = now
+	}
+	if b.cfg.OnStateChange != nil {
+		b.changes = append(b.changes, stateChange{host: host, from: from, to: state})
+	}
+}
+
+// unlock unlocks mu then calls OnStateChange, so the callback can use the SuperAgent.
+func (b *circuitBreaker) unlock() {
+	changes := b.changes
+	b.changes = nil
+	b.mu.Unlock()
+	for _, change := range changes {
+		b.cfg.OnStateChange(change.host, change.from, change.to)
+	}
+}
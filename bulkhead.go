@@ -0,0 +1,166 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrConcurrencyLimit is returned, wrapped, when a request can't get a slot of MaxConcurrent
+// because the queue is full, the queue timeout is over or the context is done, check it with errors.Is.
+var ErrConcurrencyLimit = errors.New("too many concurrent requests")
+
+// bulkhead holds a semaphore per host, it's shared by the clones of a SuperAgent.
+type bulkhead struct {
+	mu           sync.Mutex
+	limit        int            // in-flight requests of the hosts without an override, 0 means no limit
+	hosts        map[string]int // per host overrides
+	queue        int            // requests allowed to wait for a slot, -1 means no limit
+	queueTimeout time.Duration  // 0 means no timeout
+	sems         map[string]*semaphore
+}
+
+type semaphore struct {
+	slots   chan struct{}
+	waiting int
+}
+
+func newBulkhead() *bulkhead {
+	return &bulkhead{
+		hosts: make(map[string]int),
+		queue: -1,
+		sems:  make(map[string]*semaphore),
+	}
+}
+
+// MaxConcurrent limits the requests in flight to n for each host, n 0 means no limit.
+// A request is in flight until its response body is closed, so the body of EndStream must be closed.
+// When all the slots are taken, up to queue requests wait for one, at most queueTimeout if it's not 0,
+// and the others fail fast with ErrConcurrencyLimit; queue -1 means no limit. The wait is aborted when
+// the context given to Context is done and its duration is recorded in Stats.QueueWait.
+// The slots are shared by the clones of the SuperAgent made after this call.
+// Example. To send at most 8 requests at once to each host, with 100 waiting at most 1s
+//
+//	base := gorequest.New().
+//	  MaxConcurrent(8, 100, time.Second).
+//	  MaxConcurrentHost("slow.example.com", 2)
+//	resp, body, errs := base.Clone().Get("https://slow.example.com/report").End()
+func (s *SuperAgent) MaxConcurrent(n, queue int, queueTimeout time.Duration) *SuperAgent {
+	b := newBulkhead()
+	b.limit = max(n, 0)
+	b.queue = max(queue, -1)
+	b.queueTimeout = queueTimeout
+	if s.bulkhead != nil {
+		s.bulkhead.mu.Lock()
+		for host, limit := range s.bulkhead.hosts {
+			b.hosts[host] = limit
+		}
+		s.bulkhead.mu.Unlock()
+	}
+	s.bulkhead = b
+	return s
+}
+
+// MaxConcurrentHost overrides the limit of MaxConcurrent for host, which is the host of the request url,
+// including the port when there is one. It changes the slots shared with the clones of the SuperAgent.
+func (s *SuperAgent) MaxConcurrentHost(host string, n int) *SuperAgent {
+	if s.bulkhead == nil {
+		s.bulkhead = newBulkhead()
+	}
+	s.bulkhead.mu.Lock()
+	s.bulkhead.hosts[host] = max(n, 0)
+	delete(s.bulkhead.sems, host)
+	s.bulkhead.mu.Unlock()
+	return s
+}
+
+func (s *SuperAgent) bulkheadMiddleware(next Handler) Handler {
+	b := s.bulkhead
+	return func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		release, err := b.acquire(req.Context(), req.URL.Host)
+		s.Stats.QueueWait = time.Since(start)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next(req)
+		if err != nil || resp.Body == nil {
+			release()
+			return resp, err
+		}
+		resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+		return resp, nil
+	}
+}
+
+// acquire takes a slot for host and returns the func giving it back.
+func (b *bulkhead) acquire(ctx context.Context, host string) (func(), error) {
+	b.mu.Lock()
+	limit, ok := b.hosts[host]
+	if !ok {
+		limit = b.limit
+	}
+	if limit == 0 {
+		b.mu.Unlock()
+		return func() {}, nil
+	}
+	sem, ok := b.sems[host]
+	if !ok {
+		sem = &semaphore{slots: make(chan struct{}, limit)}
+		b.sems[host] = sem
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() { <-sem.slots })
+	}
+	select {
+	case sem.slots <- struct{}{}:
+		b.mu.Unlock()
+		return release, nil
+	default:
+	}
+	if b.queue >= 0 && sem.waiting >= b.queue {
+		b.mu.Unlock()
+		return nil, fmt.Errorf("%w for host %s: queue is full", ErrConcurrencyLimit, host)
+	}
+	sem.waiting++
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		sem.waiting--
+		b.mu.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+	if b.queueTimeout > 0 {
+		timer := time.NewTimer(b.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case sem.slots <- struct{}{}:
+		return release, nil
+	case <-timeout:
+		return nil, fmt.Errorf("%w for host %s: no slot after %s", ErrConcurrencyLimit, host, b.queueTimeout)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w for host %s: %w", ErrConcurrencyLimit, host, ctx.Err())
+	}
+}
+
+// releaseOnClose gives the slot of a request back when its response body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
@@ -0,0 +1,338 @@
+package gorequest
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheStatus tells how Cache answered a request, it's recorded in Stats.Cache and in the
+// X-Gorequest-Cache header of the response.
+type CacheStatus string
+
+const (
+	// CacheHit is a fresh response served from the cache without contacting the server.
+	CacheHit CacheStatus = "HIT"
+	// CacheRevalidated is a stale response the server confirmed with 304 Not Modified,
+	// the cached body is served.
+	CacheRevalidated CacheStatus = "REVALIDATED"
+	// CacheMiss is a response from the server, it may have been stored.
+	CacheMiss CacheStatus = "MISS"
+)
+
+// HeaderCache is the response header set to the CacheStatus by Cache.
+const HeaderCache = "X-Gorequest-Cache"
+
+// CacheEntry is a response stored by a CacheStore.
+type CacheEntry struct {
+	StatusCode int
+	Status     string
+	Proto      string
+	Header     http.Header
+	Body       []byte
+	// Vary holds the request headers named by the Vary header of the response.
+	Vary http.Header
+	// RequestTime and ResponseTime are when the request was sent and the response was received.
+	RequestTime  time.Time
+	ResponseTime time.Time
+}
+
+// CacheStore stores the responses of Cache, it must be safe for concurrent use.
+// See NewMemoryCache and NewDiskCache.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// heuristicStatus are the status codes cacheable without explicit freshness, RFC 9110 section 15.1.
+var heuristicStatus = []int{200, 203, 204, 206, 300, 301, 308, 404, 405, 410, 414, 501}
+
+// Cache stores the responses of GET requests in store and reuses them following RFC 9111 as a
+// private cache: fresh responses, per Cache-Control max-age or Expires, are served without contacting
+// the server, and stale ones are revalidated with If-None-Match and If-Modified-Since, the cached body
+// being served on 304 Not Modified. Stats.Cache and the X-Gorequest-Cache header of the response
+// tell whether it was a hit, a revalidation or a miss.
+// Responses with Cache-Control no-store and range requests are not stored, and successful unsafe requests such as POST
+// invalidate the response stored for their url.
+// Example. To cache up to 1000 responses in memory
+//
+//	base := gorequest.New().Cache(gorequest.NewMemoryCache(1000))
+//	resp, body, errs := base.Clone().Get("https://example.com/config").End()
+func (s *SuperAgent) Cache(store CacheStore) *SuperAgent {
+	s.cache = store
+	return s
+}
+
+func cacheKey(req *http.Request) string {
+	return http.MethodGet + " " + req.URL.String()
+}
+
+func (s *SuperAgent) cacheMiddleware(next Handler) Handler {
+	store := s.cache
+	return func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			resp, err := next(req)
+			if err == nil && !isSafeMethod(req.Method) && resp.StatusCode < 400 {
+				store.Delete(cacheKey(req))
+			}
+			return resp, err
+		}
+
+		key := cacheKey(req)
+		reqCC := parseCacheControl(req.Header.Get("Cache-Control"))
+		if reqCC.has("no-store") || req.Header.Get("Range") != "" {
+			s.Stats.Cache = CacheMiss
+			return next(req)
+		}
+
+		entry, ok := store.Get(key)
+		if ok && !entry.matchVary(req) {
+			ok = false
+		}
+		now := time.Now()
+		if ok && !reqCC.has("no-cache") && entry.fresh(now, reqCC) {
+			s.Stats.Cache = CacheHit
+			return entry.response(req, CacheHit, now), nil
+		}
+
+		sendReq := req
+		if ok {
+			etag, lastModified := entry.Header.Get("ETag"), entry.Header.Get("Last-Modified")
+			if etag != "" || lastModified != "" {
+				sendReq = req.Clone(req.Context())
+				if etag != "" {
+					sendReq.Header.Set("If-None-Match", etag)
+				}
+				if lastModified != "" {
+					sendReq.Header.Set("If-Modified-Since", lastModified)
+				}
+			}
+		}
+
+		requestTime := time.Now()
+		resp, err := next(sendReq)
+		if err != nil {
+			return nil, err
+		}
+		responseTime := time.Now()
+
+		if ok && sendReq != req && resp.StatusCode == http.StatusNotModified {
+			discardBody(resp)
+			entry = entry.updated(resp.Header, requestTime, responseTime)
+			store.Set(key, entry)
+			s.Stats.Cache = CacheRevalidated
+			return entry.response(req, CacheRevalidated, responseTime), nil
+		}
+
+		s.Stats.Cache = CacheMiss
+		if !isStorable(req, resp) {
+			resp.Header.Set(HeaderCache, string(CacheMiss))
+			return resp, nil
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		store.Set(key, &CacheEntry{
+			StatusCode:   resp.StatusCode,
+			Status:       resp.Status,
+			Proto:        resp.Proto,
+			Header:       resp.Header.Clone(),
+			Body:         body,
+			Vary:         varyHeaders(req, resp.Header),
+			RequestTime:  requestTime,
+			ResponseTime: responseTime,
+		})
+		resp.Header.Set(HeaderCache, string(CacheMiss))
+		return resp, nil
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// isStorable reports whether resp can be stored, RFC 9111 section 3.
+func isStorable(req *http.Request, resp *http.Response) bool {
+	cc := parseCacheControl(resp.Header.Get("Cache-Control"))
+	if cc.has("no-store") || resp.Header.Get("Vary") == "*" || resp.StatusCode == http.StatusPartialContent {
+		return false
+	}
+	if req.Header.Get("Authorization") != "" && !cc.has("public") && !cc.has("must-revalidate") && !cc.has("s-maxage") {
+		return false
+	}
+	if cc.has("max-age") || cc.has("public") || cc.has("private") || resp.Header.Get("Expires") != "" {
+		return true
+	}
+	if !statusesContains(heuristicStatus, resp.StatusCode) {
+		return false
+	}
+	return resp.Header.Get("Last-Modified") != "" || resp.Header.Get("ETag") != ""
+}
+
+func varyHeaders(req *http.Request, header http.Header) http.Header {
+	vary := http.Header{}
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				vary[http.CanonicalHeaderKey(name)] = req.Header.Values(name)
+			}
+		}
+	}
+	return vary
+}
+
+func (e *CacheEntry) matchVary(req *http.Request) bool {
+	for name, values := range e.Vary {
+		if strings.Join(values, ",") != strings.Join(req.Header.Values(name), ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// fresh reports whether the entry can be served without revalidation, RFC 9111 section 4.2.
+func (e *CacheEntry) fresh(now time.Time, reqCC cacheControl) bool {
+	cc := parseCacheControl(e.Header.Get("Cache-Control"))
+	if cc.has("no-cache") {
+		return false
+	}
+	age := e.age(now)
+	lifetime := e.freshnessLifetime(cc)
+	if maxAge, ok := reqCC.seconds("max-age"); ok && maxAge < lifetime {
+		lifetime = maxAge
+	}
+	if minFresh, ok := reqCC.seconds("min-fresh"); ok {
+		age += minFresh
+	}
+	return age < lifetime
+}
+
+func (e *CacheEntry) freshnessLifetime(cc cacheControl) time.Duration {
+	if maxAge, ok := cc.seconds("max-age"); ok {
+		return maxAge
+	}
+	date := e.date()
+	if expires := e.Header.Get("Expires"); expires != "" {
+		t, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+		return t.Sub(date)
+	}
+	// heuristic freshness, RFC 9111 section 4.2.2
+	if lastModified, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil &&
+		statusesContains(heuristicStatus, e.StatusCode) && date.After(lastModified) {
+		return date.Sub(lastModified) / 10
+	}
+	return 0
+}
+
+func (e *CacheEntry) date() time.Time {
+	if date, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		return date
+	}
+	return e.ResponseTime
+}
+
+// age is the current age of the entry, RFC 9111 section 4.2.3.
+func (e *CacheEntry) age(now time.Time) time.Duration {
+	apparentAge := e.ResponseTime.Sub(e.date())
+	if apparentAge < 0 {
+		apparentAge = 0
+	}
+	var ageValue time.Duration
+	if seconds, err := strconv.Atoi(e.Header.Get("Age")); err == nil && seconds > 0 {
+		ageValue = time.Duration(seconds) * time.Second
+	}
+	correctedAge := ageValue + e.ResponseTime.Sub(e.RequestTime)
+	if correctedAge < apparentAge {
+		correctedAge = apparentAge
+	}
+	return correctedAge + now.Sub(e.ResponseTime)
+}
+
+// updated returns a copy of the entry with the headers of a 304 response, RFC 9111 section 4.3.4.
+func (e *CacheEntry) updated(header http.Header, requestTime, responseTime time.Time) *CacheEntry {
+	entry := *e
+	entry.Header = e.Header.Clone()
+	for name, values := range header {
+		switch name {
+		case "Content-Length", "Content-Encoding", "Transfer-Encoding":
+			continue
+		}
+		entry.Header[name] = append([]string(nil), values...)
+	}
+	entry.RequestTime = requestTime
+	entry.ResponseTime = responseTime
+	return &entry
+}
+
+func (e *CacheEntry) response(req *http.Request, status CacheStatus, now time.Time) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(e.age(now).Seconds())))
+	header.Set(HeaderCache, string(status))
+	proto := e.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+	major, minor, _ := http.ParseHTTPVersion(proto)
+	return &http.Response{
+		Status:        e.Status,
+		StatusCode:    e.StatusCode,
+		Proto:         proto,
+		ProtoMajor:    major,
+		ProtoMinor:    minor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheControl holds the directives of a Cache-Control header, the names are lower case.
+type cacheControl map[string]string
+
+func parseCacheControl(value string) cacheControl {
+	cc := cacheControl{}
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(directive, "=")
+		cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(arg), `"`)
+	}
+	return cc
+}
+
+func (cc cacheControl) has(name string) bool {
+	_, ok := cc[name]
+	return ok
+}
+
+func (cc cacheControl) seconds(name string) (time.Duration, bool) {
+	value, ok := cc[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	if seconds > math.MaxInt64/int64(time.Second) {
+		seconds = math.MaxInt64 / int64(time.Second)
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,176 @@
+package gorequest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// Codec encodes the request body of a type given to Type and decodes response bodies of its media type.
+type Codec interface {
+	// Encode builds the request body from the data of s, such as Data, SliceData or RawString.
+	// A nil body means there is nothing to send, an empty contentType means the media type the codec
+	// is registered with.
+	Encode(s *SuperAgent) (body io.Reader, contentType string, err error)
+	// Decode decodes a response body into v, which is a non-nil pointer.
+	Decode(data []byte, v any) error
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{
+		TypeJSON:       jsonCodec{},
+		TypeXML:        xmlCodec{},
+		TypeForm:       formCodec{},
+		TypeFormData:   formCodec{},
+		TypeUrlencoded: formCodec{},
+		TypeHTML:       textCodec{},
+		TypeText:       textCodec{},
+		TypeMultipart:  multipartCodec{},
+	}
+	// codecMimes are the media types of the codecs, the exported Types only holds the built-in ones
+	// so it's never written once the program started.
+	codecMimes = maps.Clone(Types)
+)
+
+// RegisterCodec registers a codec for the type name, which can then be used with Type,
+// and for the media type mime: requests whose Content-Type header is mime use the codec,
+// and EndAs decodes responses of that media type with it.
+// Registering an existing name replaces its codec, it's safe for concurrent use and it doesn't change Types.
+//
+//	gorequest.RegisterCodec("yaml", "application/yaml", yamlCodec{})
+//	gorequest.New().
+//	  Post("https://example.com").
+//	  Type("yaml").
+//	  Send(config).
+//	  End()
+func RegisterCodec(name, mime string, codec Codec) {
+	if name == "" || mime == "" || codec == nil {
+		return
+	}
+	codecsMu.Lock()
+	codecs[name] = codec
+	codecMimes[name] = mime
+	codecsMu.Unlock()
+
+	RegisterDecoder(mime, codec.Decode)
+}
+
+func codecFor(name string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[name]
+	return codec, ok
+}
+
+// mimeFor returns the media type of the type name, empty when it's not registered.
+func mimeFor(name string) string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	return codecMimes[name]
+}
+
+// jsonCodec sends Data as a json object or SliceData as a json array, and RawString when they're mixed.
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(s *SuperAgent) (io.Reader, string, error) {
+	// If-case to give support to json array. we check if
+	// 1) Map only: send it as json map from s.Data
+	// 2) Array or Mix of map & array or others: send it as rawstring from s.RawString
+	var contentJson []byte
+	if s.BounceToRawString {
+		contentJson = StringToBytes(s.RawString)
+	} else if len(s.Data) != 0 {
+		contentJson, _ = json.Marshal(s.Data)
+	} else if len(s.SliceData) != 0 {
+		contentJson, _ = json.Marshal(s.SliceData)
+	}
+	if contentJson == nil {
+		return nil, "", nil
+	}
+	return bytes.NewReader(contentJson), "", nil
+}
+
+func (jsonCodec) Decode(data []byte, v any) error {
+	return decodeJSON(data, v)
+}
+
+// formCodec sends Data form-urlencoded, and RawString when there is SliceData.
+type formCodec struct{}
+
+func (formCodec) Encode(s *SuperAgent) (io.Reader, string, error) {
+	var contentForm []byte
+	if s.BounceToRawString || len(s.SliceData) != 0 {
+		contentForm = StringToBytes(s.RawString)
+	} else {
+		formData := changeMapToURLValues(s.Data)
+		contentForm = StringToBytes(formData.Encode())
+	}
+	if len(contentForm) == 0 {
+		return nil, "", nil
+	}
+	return bytes.NewReader(contentForm), "", nil
+}
+
+func (formCodec) Decode(data []byte, v any) error {
+	return decodeForm(data, v)
+}
+
+// textCodec sends RawString as is.
+type textCodec struct{}
+
+func (textCodec) Encode(s *SuperAgent) (io.Reader, string, error) {
+	if len(s.RawString) == 0 {
+		return nil, "", nil
+	}
+	return strings.NewReader(s.RawString), "", nil
+}
+
+func (textCodec) Decode(data []byte, v any) error {
+	return decodeText(data, v)
+}
+
+// xmlCodec sends a struct given to Send marshaled with encoding/xml, or RawString as is.
+type xmlCodec struct{}
+
+func (xmlCodec) Encode(s *SuperAgent) (io.Reader, string, error) {
+	switch len(s.StructData) {
+	case 0:
+	case 1:
+		contentXml, err := xml.Marshal(s.StructData[0])
+		if err != nil {
+			return nil, "", err
+		}
+		return bytes.NewReader(contentXml), "", nil
+	default:
+		return nil, "", fmt.Errorf("xml body can only be one struct, got %d", len(s.StructData))
+	}
+	if len(s.RawString) == 0 {
+		return nil, "", nil
+	}
+	return strings.NewReader(s.RawString), "", nil
+}
+
+func (xmlCodec) Decode(data []byte, v any) error {
+	return decodeXML(data, v)
+}
+
+// multipartCodec sends RawString, Data, SliceData and FileData as multipart/form-data parts.
+type multipartCodec struct{}
+
+func (multipartCodec) Encode(s *SuperAgent) (io.Reader, string, error) {
+	body, err := s.multipartBody()
+	if err != nil || body == nil {
+		return nil, "", err
+	}
+	return body, body.contentType, nil
+}
+
+func (multipartCodec) Decode(_ []byte, v any) error {
+	return fmt.Errorf("can't decode multipart into %T", v)
+}
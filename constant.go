@@ -23,6 +23,7 @@ const (
 	TypeMultipart  = "multipart"
 )
 
+// Types are the media types of the built-in types, the types of RegisterCodec are not added to it.
 var Types = map[string]string{
 	TypeJSON:       "application/json",
 	TypeXML:        "application/xml",
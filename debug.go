@@ -23,9 +23,9 @@ func (s *SuperAgent) debuggingRequest(req *http.Request) {
 	}
 }
 
-func (s *SuperAgent) debuggingResponse(resp *http.Response) {
+func (s *SuperAgent) debuggingResponse(resp *http.Response, body bool) {
 	if s.Debug {
-		dump, err := httputil.DumpResponse(resp, true)
+		dump, err := httputil.DumpResponse(resp, body)
 		if nil != err {
 			s.logger.Println("Error:", err)
 		} else {
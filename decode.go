@@ -0,0 +1,224 @@
+package gorequest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Decoder decodes a response body into v, which is a non-nil pointer.
+type Decoder func(data []byte, v any) error
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		MIMEJSON:                            decodeJSON,
+		"application/xml":                   decodeXML,
+		"text/xml":                          decodeXML,
+		"application/x-www-form-urlencoded": decodeForm,
+		"text/plain":                        decodeText,
+		"text/html":                         decodeText,
+	}
+)
+
+// RegisterDecoder registers the decoder used by EndAs for responses of the given media type,
+// e.g. "application/yaml". It replaces the decoder already registered for that media type.
+func RegisterDecoder(mediaType string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	if decoder == nil {
+		delete(decoders, strings.ToLower(mediaType))
+		return
+	}
+	decoders[strings.ToLower(mediaType)] = decoder
+}
+
+// decoderFor returns the decoder of the media type in contentType, "+json" and "+xml"
+// structured syntax suffixes fall back to the JSON and XML decoders.
+func decoderFor(contentType string) (Decoder, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("response content-type %q can't be parsed: %w", contentType, err)
+	}
+
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	if decoder, ok := decoders[mediaType]; ok {
+		return decoder, nil
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return decoders[MIMEJSON], nil
+	case strings.HasSuffix(mediaType, "+xml"):
+		return decoders["application/xml"], nil
+	}
+	return nil, fmt.Errorf("no decoder registered for response content-type %s", mediaType)
+}
+
+// EndAs should be used when you want the body decoded into a value of type T.
+// The decoder is picked from the response Content-Type: JSON, XML, form-urlencoded and
+// plain text are supported, and more can be added with RegisterDecoder.
+// When T is string or []byte the raw body is returned whatever the Content-Type is.
+// All errors are returned as one error like Do.
+//
+//	resp, user, err := gorequest.EndAs[User](gorequest.New().Get("https://example.com/user/1"))
+func EndAs[T any](s *SuperAgent) (Response, T, error) {
+	var v T
+	resp, body, errs := s.EndBytes()
+	if errs != nil {
+		return resp, v, joinErrors(errs)
+	}
+
+	switch p := any(&v).(type) {
+	case *string:
+		*p = string(body)
+		return resp, v, nil
+	case *[]byte:
+		*p = body
+		return resp, v, nil
+	}
+
+	if len(body) == 0 {
+		return resp, v, nil
+	}
+
+	decoder, err := decoderFor(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return resp, v, s.newError(PhaseDecode, err)
+	}
+	if err := decoder(body, &v); err != nil {
+		return resp, v, s.newError(PhaseDecode, fmt.Errorf("response body decode fail: %w", err))
+	}
+	return resp, v, nil
+}
+
+func decodeJSON(data []byte, v any) error {
+	return json.Unmarshal(bytes.TrimPrefix(data, StringToBytes("\xef\xbb\xbf")), v)
+}
+
+func decodeXML(data []byte, v any) error {
+	return xml.Unmarshal(data, v)
+}
+
+func decodeText(data []byte, v any) error {
+	switch p := v.(type) {
+	case *string:
+		*p = string(data)
+	case *[]byte:
+		*p = shallowCopyBytes(data)
+	default:
+		return fmt.Errorf("can't decode text into %T", v)
+	}
+	return nil
+}
+
+// decodeForm decodes a form-urlencoded body into url.Values, a map of strings or a struct.
+// Struct fields are matched by their `form` tag or their name, preferring an exact match
+// but accepting a case-insensitive match like encoding/json.
+func decodeForm(data []byte, v any) error {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+
+	switch p := v.(type) {
+	case *url.Values:
+		*p = values
+		return nil
+	case *map[string][]string:
+		*p = values
+		return nil
+	case *map[string]string:
+		m := make(map[string]string, len(values))
+		for k := range values {
+			m[k] = values.Get(k)
+		}
+		*p = m
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("can't decode form into %T", v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("form"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		vals, ok := values[name]
+		if !ok {
+			vals = lookupFold(values, name)
+		}
+		if len(vals) == 0 {
+			continue
+		}
+		if err := setFormField(rv.Field(i), vals); err != nil {
+			return fmt.Errorf("form field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func lookupFold(values url.Values, name string) []string {
+	for k, vals := range values {
+		if strings.EqualFold(k, name) {
+			return vals
+		}
+	}
+	return nil
+}
+
+func setFormField(field reflect.Value, vals []string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(vals[0])
+	case reflect.Bool:
+		b, err := strconv.ParseBool(vals[0])
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(vals[0], 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(vals[0], 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(vals[0], field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		field.Set(reflect.ValueOf(append([]string(nil), vals...)).Convert(field.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
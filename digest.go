@@ -0,0 +1,309 @@
+package gorequest
+
+import (
+	"crypto/md5" //nolint:gosec // md5 is required by servers using the MD5 digest algorithm
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// digestAlgorithms are the supported digest algorithms, strongest first.
+var digestAlgorithms = []struct {
+	name string
+	hash func() hash.Hash
+}{
+	{"SHA-512-256", sha512.New512_256},
+	{"SHA-256", sha256.New},
+	{"MD5", md5.New},
+}
+
+// SetDigestAuth sets the credentials of HTTP Digest authentication, RFC 7616.
+// The first request is sent without credentials, the server answers 401 Unauthorized with a challenge
+// in WWW-Authenticate and the request is replayed with the Authorization header. The challenge and the
+// nonce count are kept for the next requests of the SuperAgent and its clones, so they are sent with
+// credentials at once until the server asks again, when the nonce is stale for instance.
+// MD5, SHA-256, SHA-512-256 and their -sess variants are supported, with qop auth and auth-int.
+// Example. To send credentials to a device only accepting Digest authentication
+//
+//	gorequest.New().
+//	  Get("http://192.168.1.1/api/status").
+//	  SetDigestAuth("admin", "secret").
+//	  End()
+func (s *SuperAgent) SetDigestAuth(username string, password string) *SuperAgent {
+	s.authenticator = &digestAuth{username: username, password: password}
+	return s
+}
+
+// digestAuth is the ChallengeAuthenticator of SetDigestAuth.
+type digestAuth struct {
+	username string
+	password string
+
+	mu        sync.Mutex
+	challenge *digestChallenge
+	nc        uint32
+	cnonce    string
+}
+
+// digestChallenge is a Digest challenge of WWW-Authenticate.
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	hash      func() hash.Hash
+	sess      bool
+	qop       string
+	userhash  bool
+}
+
+func (d *digestAuth) Apply(req *http.Request) error {
+	d.mu.Lock()
+	challenge := d.challenge
+	if challenge == nil {
+		d.mu.Unlock()
+		return nil
+	}
+	d.nc++
+	nc := fmt.Sprintf("%08x", d.nc)
+	cnonce := d.cnonce
+	d.mu.Unlock()
+
+	h := func(data string) string {
+		hasher := challenge.hash()
+		hasher.Write([]byte(data))
+		return hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	uri := req.URL.RequestURI()
+	ha1 := h(d.username + ":" + challenge.realm + ":" + d.password)
+	if challenge.sess {
+		ha1 = h(ha1 + ":" + challenge.nonce + ":" + cnonce)
+	}
+	a2 := req.Method + ":" + uri
+	if challenge.qop == "auth-int" {
+		bodyHash, err := digestBodyHash(req, challenge.hash)
+		if err != nil {
+			return err
+		}
+		a2 += ":" + bodyHash
+	}
+	var response string
+	if challenge.qop == "" {
+		response = h(ha1 + ":" + challenge.nonce + ":" + h(a2))
+	} else {
+		response = h(ha1 + ":" + challenge.nonce + ":" + nc + ":" + cnonce + ":" + challenge.qop + ":" + h(a2))
+	}
+
+	username := d.username
+	if challenge.userhash {
+		username = h(d.username + ":" + challenge.realm)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `Digest username=%s, realm=%s, nonce=%s, uri=%s, algorithm=%s, response=%s`,
+		quoteParam(username), quoteParam(challenge.realm), quoteParam(challenge.nonce), quoteParam(uri),
+		challenge.algorithm, quoteParam(response))
+	if challenge.opaque != "" {
+		fmt.Fprintf(&b, ", opaque=%s", quoteParam(challenge.opaque))
+	}
+	if challenge.qop != "" {
+		fmt.Fprintf(&b, ", qop=%s, nc=%s, cnonce=%s", challenge.qop, nc, quoteParam(cnonce))
+	}
+	if challenge.userhash {
+		b.WriteString(", userhash=true")
+	}
+	req.Header.Set("Authorization", b.String())
+	return nil
+}
+
+// Challenge keeps the Digest challenge of the response. The request is not replayed when the server
+// rejected credentials computed with the same nonce, unless it says the nonce is stale.
+func (d *digestAuth) Challenge(req *http.Request, resp *http.Response) (bool, error) {
+	var challenge *digestChallenge
+	stale := false
+	for _, c := range parseAuthChallenges(resp.Header.Values("WWW-Authenticate")) {
+		if !strings.EqualFold(c.scheme, "Digest") {
+			continue
+		}
+		if candidate := newDigestChallenge(c.params); candidate != nil && (challenge == nil ||
+			algorithmRank(candidate.algorithm) < algorithmRank(challenge.algorithm)) {
+			challenge = candidate
+			stale = strings.EqualFold(c.params["stale"], "true")
+		}
+	}
+	if challenge == nil {
+		return false, nil
+	}
+	if !stale {
+		for _, c := range parseAuthChallenges(req.Header.Values("Authorization")) {
+			if strings.EqualFold(c.scheme, "Digest") && c.params["nonce"] == challenge.nonce {
+				return false, nil
+			}
+		}
+	}
+
+	cnonce, err := newCnonce()
+	if err != nil {
+		return false, err
+	}
+	d.mu.Lock()
+	d.challenge = challenge
+	d.nc = 0
+	d.cnonce = cnonce
+	d.mu.Unlock()
+	return true, nil
+}
+
+// newDigestChallenge returns the challenge of the params of a Digest challenge, nil when it's not supported.
+func newDigestChallenge(params map[string]string) *digestChallenge {
+	if params["nonce"] == "" {
+		return nil
+	}
+	challenge := &digestChallenge{
+		realm:    params["realm"],
+		nonce:    params["nonce"],
+		opaque:   params["opaque"],
+		userhash: strings.EqualFold(params["userhash"], "true"),
+	}
+
+	algorithm := params["algorithm"]
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+	name, sess := strings.CutSuffix(strings.ToUpper(algorithm), "-SESS")
+	for _, supported := range digestAlgorithms {
+		if name == supported.name {
+			challenge.hash = supported.hash
+			challenge.algorithm = supported.name
+			if sess {
+				challenge.algorithm += "-sess"
+			}
+			challenge.sess = sess
+		}
+	}
+	if challenge.hash == nil {
+		return nil
+	}
+
+	// without qop, it's the RFC 2069 compatibility mode
+	if qop, ok := params["qop"]; ok {
+		for _, value := range strings.Split(qop, ",") {
+			switch value = strings.TrimSpace(strings.ToLower(value)); value {
+			case "auth":
+				challenge.qop = value
+			case "auth-int":
+				if challenge.qop == "" {
+					challenge.qop = value
+				}
+			}
+		}
+		if challenge.qop == "" {
+			return nil
+		}
+	}
+	return challenge
+}
+
+func algorithmRank(algorithm string) int {
+	name := strings.TrimSuffix(algorithm, "-sess")
+	for i, supported := range digestAlgorithms {
+		if name == supported.name {
+			return i
+		}
+	}
+	return len(digestAlgorithms)
+}
+
+// digestBodyHash returns the hash of the request body for qop auth-int.
+func digestBodyHash(req *http.Request, newHash func() hash.Hash) (string, error) {
+	hasher := newHash()
+	if err := hashBody(req, req.GetBody, hasher); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func newCnonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func quoteParam(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// authChallenge is a challenge of WWW-Authenticate, or the credentials of Authorization.
+type authChallenge struct {
+	scheme string
+	// params holds the auth-params, the names are lower case.
+	params map[string]string
+}
+
+// parseAuthChallenges parses the challenges of WWW-Authenticate header values, RFC 9110 section 11.6.1.
+// A header value may hold several challenges separated by commas.
+func parseAuthChallenges(values []string) []authChallenge {
+	var challenges []authChallenge
+	for _, value := range values {
+		for value != "" {
+			value = strings.TrimLeft(value, " \t,")
+			if value == "" {
+				break
+			}
+			var token string
+			token, value = cutToken(value)
+			if token == "" {
+				// not a token, skip a char to keep going
+				value = value[1:]
+				continue
+			}
+			rest := strings.TrimLeft(value, " \t")
+			if !strings.HasPrefix(rest, "=") || len(challenges) == 0 {
+				challenges = append(challenges, authChallenge{scheme: token, params: map[string]string{}})
+				continue
+			}
+			var param string
+			param, value = cutParamValue(strings.TrimLeft(rest[1:], " \t"))
+			challenges[len(challenges)-1].params[strings.ToLower(token)] = param
+		}
+	}
+	return challenges
+}
+
+func cutToken(value string) (string, string) {
+	i := strings.IndexAny(value, " \t,=\"")
+	if i < 0 {
+		return value, ""
+	}
+	return value[:i], value[i:]
+}
+
+// cutParamValue returns the token or quoted-string at the start of value and what follows it.
+func cutParamValue(value string) (string, string) {
+	if !strings.HasPrefix(value, `"`) {
+		return cutToken(value)
+	}
+	var b strings.Builder
+	for i := 1; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\':
+			if i+1 < len(value) {
+				i++
+				b.WriteByte(value[i])
+			}
+		case '"':
+			return b.String(), value[i+1:]
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), ""
+}
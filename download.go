@@ -0,0 +1,224 @@
+package gorequest
+
+import (
+	"crypto/md5" //nolint:gosec // md5 is only used to verify checksums given by the caller
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned, wrapped, by EndToFile when the downloaded file doesn't match
+// the checksum of DownloadOptions.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// DownloadOptions configures EndToFile.
+type DownloadOptions struct {
+	// Resume continues a previous download from its partial file with a Range request, when the
+	// partial file has an ETag or a Last-Modified date to send in If-Range. The server sends the
+	// whole file again when it changed.
+	Resume bool
+	// SHA256 and MD5 are the expected hex digests of the file, empty to skip the check.
+	SHA256 string
+	MD5    string
+	// Perm is the permission of the file, 0644 by default.
+	Perm os.FileMode
+}
+
+// partialMeta is saved next to the partial file to resume the download with If-Range.
+type partialMeta struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// EndToFile streams the response body to the file at path without holding it in memory.
+// The body is written to path + ".part", which is renamed to path once complete and verified,
+// so path never holds a partial file. With opts.Resume an interrupted download continues where
+// it stopped, see DownloadOptions. Statuses other than 200 and 206 are errors.
+// Stats.ResponseBytes is the number of bytes received by this call.
+//
+//	resp, errs := gorequest.New().
+//	  Get("https://example.com/dataset.tar.gz").
+//	  EndToFile("./dataset.tar.gz", gorequest.DownloadOptions{
+//	    Resume: true,
+//	    SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+//	  })
+func (s *SuperAgent) EndToFile(path string, opts DownloadOptions) (Response, []error) {
+	partPath := path + ".part"
+	metaPath := partPath + ".json"
+	if opts.Perm == 0 {
+		opts.Perm = 0o644
+	}
+
+	var offset int64
+	if opts.Resume {
+		offset = s.setResumeHeaders(partPath, metaPath)
+	}
+	resp, errs := s.getResponseWithRetry()
+	if offset > 0 {
+		s.Header.Del("Range")
+		s.Header.Del("If-Range")
+	}
+	if len(errs) != 0 {
+		return resp, errs
+	}
+	defer resp.Body.Close()
+
+	body := io.Reader(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		offset = 0
+		meta := partialMeta{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+		if err := writePartialMeta(metaPath, meta); err != nil {
+			return resp, s.fileError(PhaseReadBody, err)
+		}
+	case http.StatusPartialContent:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			return resp, s.fileError(PhaseReadBody, fmt.Errorf("unexpected Content-Range %q, expected start %d",
+				resp.Header.Get("Content-Range"), offset))
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file may already be complete
+		if offset == 0 || resp.Header.Get("Content-Range") != "bytes */"+strconv.FormatInt(offset, 10) {
+			return resp, s.fileError(PhaseStatus, s.httpError(resp, nil))
+		}
+		body = http.NoBody
+	default:
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxHTTPErrorBody))
+		return resp, s.fileError(PhaseStatus, s.httpError(resp, snippet))
+	}
+
+	written, sums, err := writePartial(partPath, offset, body, opts)
+	s.Stats.ResponseBytes = written
+	if err != nil {
+		return resp, s.fileError(PhaseReadBody, err)
+	}
+	for _, sum := range sums {
+		if !strings.EqualFold(sum.got, sum.want) {
+			_ = os.Remove(partPath)
+			_ = os.Remove(metaPath)
+			return resp, s.fileError(PhaseReadBody, fmt.Errorf("%w: expected %s %s, got %s",
+				ErrChecksumMismatch, sum.name, sum.want, sum.got))
+		}
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return resp, s.fileError(PhaseReadBody, err)
+	}
+	_ = os.Remove(metaPath)
+	return resp, nil
+}
+
+func (s *SuperAgent) fileError(phase Phase, err error) []error {
+	s.appendError(phase, err)
+	return s.Errors
+}
+
+// setResumeHeaders sets Range and If-Range to resume from the partial file, it returns its size.
+func (s *SuperAgent) setResumeHeaders(partPath, metaPath string) int64 {
+	info, err := os.Stat(partPath)
+	if err != nil || info.Size() == 0 {
+		return 0
+	}
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return 0
+	}
+	var meta partialMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return 0
+	}
+	// If-Range needs a strong validator, weak etags are not allowed
+	validator := meta.ETag
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = meta.LastModified
+	}
+	if validator == "" {
+		return 0
+	}
+	s.Header.Set("Range", "bytes="+strconv.FormatInt(info.Size(), 10)+"-")
+	s.Header.Set("If-Range", validator)
+	return info.Size()
+}
+
+func writePartialMeta(path string, meta partialMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+type checksum struct {
+	name string
+	hash hash.Hash
+	want string
+	got  string
+}
+
+// writePartial writes body to the partial file from offset and computes the checksums of the whole file.
+func writePartial(partPath string, offset int64, body io.Reader, opts DownloadOptions) (int64, []checksum, error) {
+	var sums []checksum
+	if opts.SHA256 != "" {
+		sums = append(sums, checksum{name: "sha256", hash: sha256.New(), want: opts.SHA256})
+	}
+	if opts.MD5 != "" {
+		sums = append(sums, checksum{name: "md5", hash: md5.New(), want: opts.MD5}) //nolint:gosec // checksum given by the caller
+	}
+	hashes := make([]io.Writer, 0, len(sums))
+	for _, sum := range sums {
+		hashes = append(hashes, sum.hash)
+	}
+
+	f, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, opts.Perm)
+	if err != nil {
+		return 0, nil, err
+	}
+	written, err := copyPartial(f, offset, body, hashes)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return written, nil, err
+	}
+	for i := range sums {
+		sums[i].got = hex.EncodeToString(sums[i].hash.Sum(nil))
+	}
+	return written, sums, nil
+}
+
+func copyPartial(f *os.File, offset int64, body io.Reader, hashes []io.Writer) (int64, error) {
+	if offset > 0 {
+		// hash the content already downloaded, this leaves f at the end of it
+		if _, err := io.CopyN(io.MultiWriter(hashes...), f, offset); err != nil {
+			return 0, err
+		}
+	} else if err := f.Truncate(0); err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(io.MultiWriter(append(hashes, f)...), body)
+	if err != nil {
+		return written, err
+	}
+	return written, f.Sync()
+}
+
+// contentRangeStart returns the first byte position of a Content-Range header such as "bytes 100-199/200".
+func contentRangeStart(value string) (int64, bool) {
+	value, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,115 @@
+package gorequest
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// Phase is the stage of a request where an Error happened.
+type Phase string
+
+const (
+	// PhaseBuild is for errors of the builder methods such as Type, Retry, Send or SendFile.
+	PhaseBuild Phase = "build"
+	// PhaseRequest is for errors of MakeRequest.
+	PhaseRequest Phase = "request"
+	// PhaseTransport is for errors of sending the request through the middlewares and the http.Client,
+	// e.g. DNS, dial, TLS or timeout errors.
+	PhaseTransport Phase = "transport"
+	// PhaseReadBody is for errors of reading the response body.
+	PhaseReadBody Phase = "read body"
+	// PhaseStatus is for responses whose status code is not expected, see ExpectStatus.
+	PhaseStatus Phase = "status"
+	// PhaseDecode is for errors of decoding the response body, e.g. in EndStruct or EndAs.
+	PhaseDecode Phase = "decode"
+)
+
+// Error records the phase of the request which failed and the underlying cause.
+// Its message is the message of the cause, so logging it with %v or %s doesn't show the phase,
+// the method, the url or the attempt: use errors.As to read Phase, or log String.
+// It works with errors.Is and errors.As, e.g. to check for a DNS failure:
+//
+//	var dnsErr *net.DNSError
+//	if errors.As(err, &dnsErr) {
+//	  ...
+//	}
+type Error struct {
+	Phase  Phase
+	Method string
+	// URL is the request url with the password removed.
+	URL string
+	// Attempt is the attempt number of the request, starting from 1, it's 0 for PhaseBuild.
+	Attempt int
+	Err     error
+}
+
+// Error returns the message of the underlying cause, so messages are the same as
+// before errors were wrapped. fmt uses it for %v and %s, use String for the message
+// with the request details.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// String returns the error message prefixed with the phase, method, url and attempt.
+func (e *Error) String() string {
+	msg := "gorequest: " + string(e.Phase)
+	if e.Method != "" {
+		msg += " " + e.Method
+	}
+	if e.URL != "" {
+		msg += " " + e.URL
+	}
+	if e.Attempt > 1 {
+		msg += " (attempt " + strconv.Itoa(e.Attempt) + ")"
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError wraps err with the current request details, an *Error is returned as is.
+func (s *SuperAgent) newError(phase Phase, err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	attempt := 0
+	if phase != PhaseBuild {
+		attempt = s.Retryable.Attempt + 1
+	}
+	return &Error{
+		Phase:   phase,
+		Method:  s.Method,
+		URL:     redactURL(s.Url),
+		Attempt: attempt,
+		Err:     err,
+	}
+}
+
+func (s *SuperAgent) appendError(phase Phase, err error) {
+	s.Errors = append(s.Errors, s.newError(phase, err))
+}
+
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Redacted()
+}
+
+// joinErrors returns errs as one error, nil when there is no error.
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errors.Join(errs...)
+	}
+}
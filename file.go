@@ -1,8 +1,13 @@
 package gorequest
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -14,7 +19,10 @@ type File struct {
 	Filename  string
 	Fieldname string
 	MimeType  string
-	Data      []byte
+	// Data is the content of a file given as a []byte, it's empty for a path, an os.File or an io.Reader.
+	Data []byte
+	// source streams the content when the file is sent, Data is empty then.
+	source *readerSource
 }
 
 // SendFile function works only with type "multipart". The function accepts one mandatory and up to three optional arguments. The mandatory (first) argument is the file.
@@ -44,6 +52,18 @@ type File struct {
 //	  SendFile(f).
 //	  End()
 //
+// Or any io.Reader, which is read when the request is sent:
+//
+//	gorequest.New().
+//	  Post("http://example.com").
+//	  Type("multipart").
+//	  SendFile(strings.NewReader("content"), "example_file.ext").
+//	  End()
+//
+// Files given by path or os.File and io.Reader content are streamed instead of being read into memory,
+// they're read again from the start for retries and redirects, which is only possible
+// for an io.Reader when it's also an io.Seeker.
+//
 // The first optional argument (second argument overall) is the filename, which will be automatically determined when file is a string (path) or a os.File.
 // When file is a []byte slice, filename defaults to "filename". In all cases the automatically determined filename can be overwritten:
 //
@@ -86,7 +106,7 @@ type File struct {
 //	  End()
 func (s *SuperAgent) SendFile(file any, args ...any) *SuperAgent {
 	if file == nil {
-		s.Errors = append(s.Errors, errors.New("sendFile func: nil file"))
+		s.appendError(PhaseBuild, errors.New("sendFile func: nil file"))
 		return s
 	}
 
@@ -112,7 +132,7 @@ func (s *SuperAgent) SendFile(file any, args ...any) *SuperAgent {
 	if len(args) >= 3 {
 		argSkipFileNumbering := reflect.ValueOf(args[2])
 		if !argSkipFileNumbering.IsValid() {
-			s.Errors = append(s.Errors, errors.New("the fourth SendFile method argument for skipFileNumbering cannot be nil"))
+			s.appendError(PhaseBuild, errors.New("the fourth SendFile method argument for skipFileNumbering cannot be nil"))
 			return s
 		}
 		if argSkipFileNumbering.Kind() == reflect.Bool {
@@ -126,7 +146,7 @@ func (s *SuperAgent) SendFile(file any, args ...any) *SuperAgent {
 			fileType = strings.TrimSpace(argFileType)
 		}
 		if fileType == "" {
-			s.Errors = append(s.Errors, errors.New("the fifth SendFile method argument for MIME type cannot be an empty string"))
+			s.appendError(PhaseBuild, errors.New("the fifth SendFile method argument for MIME type cannot be an empty string"))
 			return s
 		}
 	}
@@ -135,31 +155,46 @@ func (s *SuperAgent) SendFile(file any, args ...any) *SuperAgent {
 		fieldname = "file" + strconv.Itoa(len(s.FileData)+1)
 	}
 
+	// os.File is sent by its name below
+	_, isOSFile := file.(*os.File)
+	if r, ok := file.(io.Reader); ok && !isOSFile {
+		if filename == "" {
+			filename = "filename"
+		}
+		s.FileData = append(s.FileData, File{
+			Filename:  filename,
+			Fieldname: fieldname,
+			MimeType:  fileType,
+			source:    newReaderSource(r, -1),
+		})
+		return s
+	}
+
 	v := reflect.ValueOf(file)
 	switch v.Kind() {
 	case reflect.String:
 		pathToFile, err := filepath.Abs(v.String())
 		if err != nil {
-			s.Errors = append(s.Errors, err)
+			s.appendError(PhaseBuild, err)
 			return s
 		}
 		if filename == "" {
 			filename = filepath.Base(pathToFile)
 		}
-		data, err := os.ReadFile(v.String())
+		source, err := newFileSource(v.String())
 		if err != nil {
-			s.Errors = append(s.Errors, err)
+			s.appendError(PhaseBuild, err)
 			return s
 		}
 		s.FileData = append(s.FileData, File{
 			Filename:  filename,
 			Fieldname: fieldname,
 			MimeType:  fileType,
-			Data:      data,
+			source:    source,
 		})
 	case reflect.Slice:
 		if v.Type().Elem().Kind() != reflect.Uint8 {
-			s.Errors = append(s.Errors, errors.New("sendFile currently only supports byte slices as file content"))
+			s.appendError(PhaseBuild, errors.New("sendFile currently only supports byte slices as file content"))
 			return s
 		}
 		if filename == "" {
@@ -177,7 +212,7 @@ func (s *SuperAgent) SendFile(file any, args ...any) *SuperAgent {
 		s.FileData = append(s.FileData, f)
 	case reflect.Pointer:
 		if v.IsNil() {
-			s.Errors = append(s.Errors, errors.New("sendFile func: nil pointer"))
+			s.appendError(PhaseBuild, errors.New("sendFile func: nil pointer"))
 			return s
 		}
 		if len(args) == 1 {
@@ -199,22 +234,104 @@ func (s *SuperAgent) SendFile(file any, args ...any) *SuperAgent {
 			if filename == "" {
 				filename = filepath.Base(osfile.Name())
 			}
-			data, err := os.ReadFile(osfile.Name())
+			source, err := newFileSource(osfile.Name())
 			if err != nil {
-				s.Errors = append(s.Errors, err)
+				s.appendError(PhaseBuild, err)
 				return s
 			}
 			s.FileData = append(s.FileData, File{
 				Filename:  filename,
 				Fieldname: fieldname,
 				MimeType:  fileType,
-				Data:      data,
+				source:    source,
 			})
 			return s
 		}
 
-		s.Errors = append(s.Errors, fmt.Errorf("sendFile currently only supports either a string (path/to/file), a slice of bytes (file content itself), or a os.File"))
+		s.appendError(PhaseBuild, fmt.Errorf("sendFile currently only supports either a string (path/to/file), a slice of bytes (file content itself), a os.File or an io.Reader"))
 	}
 
 	return s
 }
+
+// multipartBody builds the multipart/form-data body, the files are streamed
+// when the request is sent. It returns nil when there is nothing to send.
+func (s *SuperAgent) multipartBody() (*streamBody, error) {
+	var (
+		buf     = &bytes.Buffer{}
+		mw      = multipart.NewWriter(buf)
+		parts   []bodyPart
+		written bool
+	)
+
+	if s.BounceToRawString {
+		fieldName := s.Header.Get("data_fieldname")
+		if fieldName == "" {
+			fieldName = "data"
+		}
+		fw, _ := mw.CreateFormField(fieldName)
+		if _, err := fw.Write(StringToBytes(s.RawString)); err != nil {
+			return nil, err
+		}
+		written = true
+	}
+
+	if len(s.Data) != 0 {
+		formData := changeMapToURLValues(s.Data)
+		for key, values := range formData {
+			for _, value := range values {
+				fw, _ := mw.CreateFormField(key)
+				if _, err := fw.Write(StringToBytes(value)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		written = true
+	}
+
+	if len(s.SliceData) != 0 {
+		fieldName := s.Header.Get("json_fieldname")
+		if fieldName == "" {
+			fieldName = "data"
+		}
+		// copied from CreateFormField() in mime/multipart/writer.go
+		h := make(textproto.MIMEHeader)
+		fieldName = strings.Replace(strings.Replace(fieldName, "\\", "\\\\", -1), `"`, "\\\"", -1)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, fieldName))
+		h.Set("Content-Type", "application/json")
+		fw, _ := mw.CreatePart(h)
+		contentJson, err := json.Marshal(s.SliceData)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fw.Write(contentJson); err != nil {
+			return nil, err
+		}
+		written = true
+	}
+
+	// add the files, the part headers are kept in memory and the content is streamed
+	for _, file := range s.FileData {
+		fw, _ := CreateFormFile(mw, file.Fieldname, file.Filename, file.MimeType)
+		if file.source == nil {
+			if _, err := fw.Write(file.Data); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		parts = append(parts, bodyPart{data: shallowCopyBytes(buf.Bytes())}, bodyPart{source: file.source})
+		buf.Reset()
+	}
+	if len(s.FileData) != 0 {
+		written = true
+	}
+
+	// close before call to FormDataContentType ! otherwise, it's not valid multipart
+	mw.Close()
+
+	if !written {
+		return nil, nil
+	}
+	parts = append(parts, bodyPart{data: buf.Bytes()})
+	return newStreamBody(parts, mw.FormDataContentType())
+}
@@ -5,14 +5,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"log"
-	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptrace"
-	"net/textproto"
 	"net/url"
 	"os"
 	"reflect"
@@ -40,6 +39,7 @@ type SuperAgent struct {
 	ForceType            string
 	Data                 map[string]any
 	SliceData            []any
+	StructData           []any
 	FormData             url.Values
 	QueryData            url.Values
 	QueryParamOrder      []queryParam
@@ -47,15 +47,31 @@ type SuperAgent struct {
 	BounceToRawString    bool
 	RawString            string
 	RawBytes             []byte
+	rawReader            *readerSource
 	Client               *http.Client
 	Transport            *http.Transport
 	Cookies              []*http.Cookie
 	Errors               []error
 	BasicAuth            basicAuth
+	authenticator        Authenticator
+	signer               requestSigner
+	publicKeyPins        *publicKeyPins
 	Debug                bool
 	CurlCommand          bool
 	logger               Logger
 	uploadProgress       UploadProgress
+	uploadDetail         func(Progress)
+	downloadProgress     DownloadProgress
+	downloadDetail       func(Progress)
+	middlewares          []Middleware
+	expectedStatus       []int
+	expectSuccess        bool
+	idempotencyKey       bool
+	breaker              *circuitBreaker
+	limiter              *rateLimiter
+	bulkhead             *bulkhead
+	hedge                hedge
+	cache                CacheStore
 	Retryable            superAgentRetryable
 	DoNotClearSuperAgent bool
 	isClone              bool
@@ -107,6 +123,7 @@ func New() *SuperAgent {
 		CurlCommand:       false,
 		logger:            log.New(os.Stderr, "[gorequest]", log.LstdFlags),
 		uploadProgress:    nil,
+		middlewares:       nil,
 		isClone:           false,
 		ctx:               nil,
 		trace:             nil,
@@ -120,6 +137,8 @@ func New() *SuperAgent {
 // concurrently.
 // Note: This does a shallow copy of the parent. So you will need to be
 // careful of Data provided
+// Note: A body read from a reader by SendReader or SendFile is only sent by the clones
+// when it's a file or an io.ReaderAt
 // Note: It re-uses the transport and copies the client. If you modify transport
 // settings on a clone, the clone will have a new transport.
 // Note: DoNotClearSuperAgent is forced to "true" after Clone
@@ -132,6 +151,7 @@ func (s *SuperAgent) Clone() *SuperAgent {
 		ForceType:            s.ForceType,
 		Data:                 shallowCopyData(s.Data),
 		SliceData:            shallowCopyDataSlice(s.SliceData),
+		StructData:           shallowCopyDataSlice(s.StructData),
 		FormData:             url.Values(cloneMapArray(s.FormData)),
 		QueryData:            url.Values(cloneMapArray(s.QueryData)),
 		QueryParamOrder:      shallowCopyQueryParams(s.QueryParamOrder),
@@ -139,15 +159,31 @@ func (s *SuperAgent) Clone() *SuperAgent {
 		BounceToRawString:    s.BounceToRawString,
 		RawString:            s.RawString,
 		RawBytes:             shallowCopyBytes(s.RawBytes),
+		rawReader:            s.rawReader.clone(),
 		Client:               cloneHttpClient(s.Client),
 		Transport:            s.Transport,
 		Cookies:              shallowCopyCookies(s.Cookies),
 		Errors:               shallowCopyErrors(s.Errors),
 		BasicAuth:            s.BasicAuth,
+		authenticator:        s.authenticator,
+		signer:               s.signer,
+		publicKeyPins:        s.publicKeyPins,
 		Debug:                s.Debug,
 		CurlCommand:          s.CurlCommand,
 		logger:               s.logger, // thread safe.. anyway
 		uploadProgress:       s.uploadProgress,
+		uploadDetail:         s.uploadDetail,
+		downloadProgress:     s.downloadProgress,
+		downloadDetail:       s.downloadDetail,
+		middlewares:          shallowCopyMiddlewares(s.middlewares),
+		expectedStatus:       append([]int(nil), s.expectedStatus...),
+		expectSuccess:        s.expectSuccess,
+		idempotencyKey:       s.idempotencyKey,
+		breaker:              s.breaker,
+		limiter:              s.limiter,
+		bulkhead:             s.bulkhead,
+		hedge:                s.hedge,
+		cache:                s.cache,
 		Retryable:            copyRetryable(s.Retryable),
 		DoNotClearSuperAgent: true,
 		isClone:              true,
@@ -187,6 +223,7 @@ func (s *SuperAgent) ClearSuperAgent() {
 	s.Header = http.Header{}
 	s.Data = make(map[string]any)
 	s.SliceData = []any{}
+	s.StructData = nil
 	s.FormData = url.Values{}
 	s.QueryData = url.Values{}
 	s.QueryParamOrder = []queryParam{}
@@ -194,6 +231,7 @@ func (s *SuperAgent) ClearSuperAgent() {
 	s.BounceToRawString = false
 	s.RawString = ""
 	s.RawBytes = nil
+	s.rawReader = nil
 	s.ForceType = ""
 	s.TargetType = TypeJSON
 	s.Cookies = make([]*http.Cookie, 0)
@@ -303,11 +341,14 @@ func (s *SuperAgent) Options(targetUrl string) *SuperAgent {
 //	"application/xml" uses "xml"
 //	"text/plain" uses "text"
 //	"application/x-www-form-urlencoded" uses "urlencoded", "form" or "form-data"
+//	"multipart/form-data" uses "multipart"
+//
+// Other types can be added with RegisterCodec.
 func (s *SuperAgent) Type(typeStr string) *SuperAgent {
-	if _, ok := Types[typeStr]; ok {
+	if mimeFor(typeStr) != "" {
 		s.ForceType = typeStr
 	} else {
-		s.Errors = append(s.Errors, fmt.Errorf("type func: incorrect type \"%s\"", typeStr))
+		s.appendError(PhaseBuild, fmt.Errorf("type func: incorrect type \"%s\"", typeStr))
 	}
 	return s
 }
@@ -354,7 +395,7 @@ func (s *SuperAgent) Query(content any) *SuperAgent {
 		s.queryMap(v.Interface())
 	case reflect.Ptr:
 		if v.IsNil() {
-			s.Errors = append(s.Errors, fmt.Errorf("query func: nil pointer"))
+			s.appendError(PhaseBuild, fmt.Errorf("query func: nil pointer"))
 		} else {
 			s.Query(v.Elem().Interface())
 		}
@@ -365,13 +406,13 @@ func (s *SuperAgent) Query(content any) *SuperAgent {
 
 func (s *SuperAgent) queryStruct(content any) *SuperAgent {
 	if marshalContent, err := json.Marshal(content); err != nil {
-		s.Errors = append(s.Errors, err)
+		s.appendError(PhaseBuild, err)
 	} else {
 		var val map[string]any
 		d := json.NewDecoder(bytes.NewBuffer(marshalContent))
 		d.UseNumber()
 		if err := d.Decode(&val); err != nil {
-			s.Errors = append(s.Errors, err)
+			s.appendError(PhaseBuild, err)
 		} else {
 			for k, v := range val {
 				// k = strings.ToLower(k)
@@ -407,7 +448,7 @@ func (s *SuperAgent) queryString(content string) *SuperAgent {
 		}
 	} else {
 		if err := s.parseOrderedQueryString(content); err != nil {
-			s.Errors = append(s.Errors, err)
+			s.appendError(PhaseBuild, err)
 		}
 	}
 	return s
@@ -501,7 +542,7 @@ func (s *SuperAgent) Send(content any) *SuperAgent {
 	// TODO: add normal text mode or other mode to Send func
 	v := reflect.ValueOf(content)
 	if !v.IsValid() {
-		s.Errors = append(s.Errors, fmt.Errorf("send func: nil content"))
+		s.appendError(PhaseBuild, fmt.Errorf("send func: nil content"))
 		return s
 	}
 
@@ -530,7 +571,7 @@ func (s *SuperAgent) Send(content any) *SuperAgent {
 		s.SendSlice(makeSliceOfReflectValue(v))
 	case reflect.Pointer:
 		if v.IsNil() {
-			s.Errors = append(s.Errors, fmt.Errorf("send func: nil pointer"))
+			s.appendError(PhaseBuild, fmt.Errorf("send func: nil pointer"))
 			return s
 		}
 		s.Send(v.Elem().Interface())
@@ -557,6 +598,7 @@ func (s *SuperAgent) SendMap(content any) *SuperAgent {
 // SendBytes sends content as a raw request body.
 func (s *SuperAgent) SendBytes(content []byte) *SuperAgent {
 	s.RawBytes = shallowCopyBytes(content)
+	s.rawReader = nil
 	return s
 }
 
@@ -567,15 +609,17 @@ func (s *SuperAgent) shouldSendRawBytes() bool {
 
 // SendStruct (similar to SendString) returns SuperAgent's itself for any next chain and takes content any as a parameter.
 // Its duty is to transform any (implicitly always a struct) into s.Data (map[string]any) which later changes into appropriate format such as json, form, text, etc. in the End() func.
+// The content itself is also kept in s.StructData, which is used by codecs needing the original value, e.g. xml.
 func (s *SuperAgent) SendStruct(content any) *SuperAgent {
+	s.StructData = append(s.StructData, content)
 	if marshalContent, err := json.Marshal(content); err != nil {
-		s.Errors = append(s.Errors, err)
+		s.appendError(PhaseBuild, err)
 	} else {
 		var val map[string]any
 		d := json.NewDecoder(bytes.NewBuffer(marshalContent))
 		d.UseNumber()
 		if err := d.Decode(&val); err != nil {
-			s.Errors = append(s.Errors, err)
+			s.appendError(PhaseBuild, err)
 		} else {
 			for k, v := range val {
 				s.Data[k] = v
@@ -745,11 +789,26 @@ func (s *SuperAgent) EndBytes(callback ...func(response Response, body []byte, e
 	return resp, body, errs
 }
 
+// Do works like EndBytes but returns the errors as one error, which is an *Error
+// or an errors.Join of them when there are several.
+//
+//	resp, body, err := gorequest.New().Get("http://example.com").Do()
+//	var dnsErr *net.DNSError
+//	if errors.As(err, &dnsErr) {
+//	  fmt.Println("dns lookup failed:", dnsErr.Name)
+//	}
+func (s *SuperAgent) Do() (Response, []byte, error) {
+	resp, body, errs := s.EndBytes()
+	return resp, body, joinErrors(errs)
+}
+
 // EndStruct should be used when you want the body as a struct. The callbacks work the same way as with `End`, except that a struct is used instead of a string.
+// The body is not decoded when there is an error, e.g. when the status code is not expected by ExpectStatus.
 func (s *SuperAgent) EndStruct(v any, callback ...func(response Response, v any, body []byte, errs []error)) (Response, []byte, []error) {
 	resp, body, errs := s.EndBytes()
 	if errs != nil {
-		return nil, body, errs
+		// the body of an unexpected status is not decoded
+		return resp, body, errs
 	}
 
 	if len(body) == 0 {
@@ -760,9 +819,9 @@ func (s *SuperAgent) EndStruct(v any, callback ...func(response Response, v any,
 	if err != nil {
 		respContentType := filterFlags(resp.Header.Get("Content-Type"))
 		if respContentType != MIMEJSON {
-			s.Errors = append(s.Errors, fmt.Errorf("response content-type is %s not application/json, so can't be json decoded: %w", respContentType, err))
+			s.appendError(PhaseDecode, fmt.Errorf("response content-type is %s not application/json, so can't be json decoded: %w", respContentType, err))
 		} else {
-			s.Errors = append(s.Errors, fmt.Errorf("response body json decode fail: %w", err))
+			s.appendError(PhaseDecode, fmt.Errorf("response body json decode fail: %w", err))
 		}
 
 		return resp, body, s.Errors
@@ -774,7 +833,78 @@ func (s *SuperAgent) EndStruct(v any, callback ...func(response Response, v any,
 	return resp, body, nil
 }
 
+// EndStream should be used when you want to consume the response body yourself, e.g. for large downloads.
+// The body is not read into memory, the caller must read and close resp.Body.
+// Retry is still applied to the response status, the body of a retried response is discarded.
+// Stats.ResponseBytes is not collected because the body has not been read yet.
+//
+//	resp, errs := gorequest.New().Get("https://example.com/export.csv").EndStream()
+//	if errs != nil {
+//	  return errs
+//	}
+//	defer resp.Body.Close()
+//	io.Copy(f, resp.Body)
+func (s *SuperAgent) EndStream() (Response, []error) {
+	return s.getResponseWithRetry()
+}
+
+// EndXML should be used when you want the body decoded from xml into v, the callbacks work the same way as with `EndStruct`.
+//
+//	var result Envelope
+//	resp, body, errs := gorequest.New().
+//	  Post("http://example.com/soap").
+//	  Type("xml").
+//	  Send(request).
+//	  EndXML(&result)
+func (s *SuperAgent) EndXML(v any, callback ...func(response Response, v any, body []byte, errs []error)) (Response, []byte, []error) {
+	resp, body, errs := s.EndBytes()
+	if errs != nil {
+		return resp, body, errs
+	}
+
+	if len(body) == 0 {
+		return resp, body, nil
+	}
+
+	if err := xml.Unmarshal(body, v); err != nil {
+		respContentType := filterFlags(resp.Header.Get("Content-Type"))
+		if respContentType != "application/xml" && respContentType != "text/xml" && !strings.HasSuffix(respContentType, "+xml") {
+			s.appendError(PhaseDecode, fmt.Errorf("response content-type is %s not application/xml, so can't be xml decoded: %w", respContentType, err))
+		} else {
+			s.appendError(PhaseDecode, fmt.Errorf("response body xml decode fail: %w", err))
+		}
+
+		return resp, body, s.Errors
+	}
+	if len(callback) != 0 {
+		respCallback := *resp
+		callback[0](&respCallback, v, body, s.Errors)
+	}
+	return resp, body, nil
+}
+
 func (s *SuperAgent) getResponseBytes() (Response, []byte, []error) {
+	resp, errs := s.getResponse(true)
+	if errs != nil {
+		return nil, nil, errs
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	// Reset resp.Body so it can be use again
+	resp.Body = io.NopCloser(bytes.NewBuffer(body))
+	if err != nil {
+		return nil, nil, []error{s.newError(PhaseReadBody, err)}
+	}
+
+	// stats collect the responseBytes
+	s.Stats.ResponseBytes = int64(len(body))
+	return resp, body, nil
+}
+
+// getResponse sends the request and returns the response with its body unread,
+// dumpBody controls whether the debug mode dumps the response body.
+func (s *SuperAgent) getResponse(dumpBody bool) (Response, []error) {
 	var (
 		req  *http.Request
 		err  error
@@ -782,14 +912,14 @@ func (s *SuperAgent) getResponseBytes() (Response, []byte, []error) {
 	)
 	// check whether there is an error. if yes, return all errors
 	if len(s.Errors) != 0 {
-		return nil, nil, s.Errors
+		return nil, s.Errors
 	}
 
 	// Make Request
 	req, err = s.MakeRequest()
 	if err != nil {
-		s.Errors = append(s.Errors, err)
-		return nil, nil, s.Errors
+		s.appendError(PhaseRequest, err)
+		return nil, s.Errors
 	}
 
 	// Set Transport
@@ -803,59 +933,48 @@ func (s *SuperAgent) getResponseBytes() (Response, []byte, []error) {
 	// Display CURL command line
 	s.logCurlCommand(req)
 
-	s.wrapUploadProgress(req)
-
 	startTime := time.Now()
 	// stats collect the requestBytes
 	s.Stats.RequestBytes = req.ContentLength
 
-	// Send request
-	resp, err = s.Client.Do(req)
+	// Send request through the middlewares
+	resp, err = s.handler()(req)
 	if err != nil {
-		s.Errors = append(s.Errors, err)
-		return nil, nil, s.Errors
+		s.appendError(PhaseTransport, err)
+		return nil, s.Errors
 	}
-	defer resp.Body.Close()
 
 	// stats collect the RequestDuration
 	s.Stats.RequestDuration = time.Since(startTime)
 
-	// Log details of this response
-	s.debuggingResponse(resp)
+	s.wrapDownloadProgress(resp)
 
-	body, err := io.ReadAll(resp.Body)
-	// Reset resp.Body so it can be use again
-	resp.Body = io.NopCloser(bytes.NewBuffer(body))
-	if err != nil {
-		return nil, nil, []error{err}
-	}
+	// Log details of this response
+	s.debuggingResponse(resp, dumpBody)
 
-	// stats collect the responseBytes
-	s.Stats.ResponseBytes = int64(len(body))
-	return resp, body, nil
+	return resp, nil
 }
 
-func (s *SuperAgent) MakeRequest() (*http.Request, error) {
+func (s *SuperAgent) MakeRequest() (req *http.Request, err error) {
 	var (
-		req           *http.Request
 		contentType   string // This is only set when the request body content is non-empty.
 		contentReader io.Reader
-		err           error
 	)
 
 	// check if there is forced type
-	switch s.ForceType {
-	case TypeJSON, TypeForm, TypeXML, TypeText, TypeMultipart:
+	if _, ok := codecFor(s.ForceType); ok {
 		s.TargetType = s.ForceType
+	} else {
 		// If forcetype is not set, check whether user set Content-Type header.
 		// If yes, also bounce to the correct supported TargetType automatically.
-	default:
 		contentType := s.Header.Get("Content-Type")
-		for k, v := range Types {
+		codecsMu.RLock()
+		for k, v := range codecMimes {
 			if contentType == v {
 				s.TargetType = k
 			}
 		}
+		codecsMu.RUnlock()
 	}
 
 	// if slice and map get mixed, let's bounce to rawstring
@@ -879,130 +998,50 @@ func (s *SuperAgent) MakeRequest() (*http.Request, error) {
 	//
 	//     https://github.com/parnurzeal/gorequest/pull/136
 	//
-	if s.RawBytes != nil {
+	if s.rawReader != nil {
+		body, err := newStreamBody([]bodyPart{{source: s.rawReader}}, "application/octet-stream")
+		if err != nil {
+			return nil, err
+		}
+		contentReader = body
+		contentType = body.contentType
+	} else if s.RawBytes != nil {
 		contentReader = bytes.NewReader(s.RawBytes)
 		contentType = "application/octet-stream"
-	} else {
-		switch s.TargetType {
-		case TypeJSON:
-			// If-case to give support to json array. we check if
-			// 1) Map only: send it as json map from s.Data
-			// 2) Array or Mix of map & array or others: send it as rawstring from s.RawString
-			var contentJson []byte
-			if s.BounceToRawString {
-				contentJson = StringToBytes(s.RawString)
-			} else if len(s.Data) != 0 {
-				contentJson, _ = json.Marshal(s.Data)
-			} else if len(s.SliceData) != 0 {
-				contentJson, _ = json.Marshal(s.SliceData)
-			}
-			if contentJson != nil {
-				contentReader = bytes.NewReader(contentJson)
-				contentType = "application/json"
-			}
-		case TypeForm, TypeFormData, TypeUrlencoded:
-			var contentForm []byte
-			if s.BounceToRawString || len(s.SliceData) != 0 {
-				contentForm = StringToBytes(s.RawString)
-			} else {
-				formData := changeMapToURLValues(s.Data)
-				contentForm = StringToBytes(formData.Encode())
-			}
-			if len(contentForm) != 0 {
-				contentReader = bytes.NewReader(contentForm)
-				contentType = "application/x-www-form-urlencoded"
-			}
-		case TypeText:
-			if len(s.RawString) != 0 {
-				contentReader = strings.NewReader(s.RawString)
-				contentType = "text/plain"
-			}
-		case TypeXML:
-			if len(s.RawString) != 0 {
-				contentReader = strings.NewReader(s.RawString)
-				contentType = "application/xml"
-			}
-		case TypeMultipart:
-			var (
-				buf = &bytes.Buffer{}
-				mw  = multipart.NewWriter(buf)
-			)
-
-			if s.BounceToRawString {
-				fieldName := s.Header.Get("data_fieldname")
-				if fieldName == "" {
-					fieldName = "data"
-				}
-				fw, _ := mw.CreateFormField(fieldName)
-				if _, err := fw.Write(StringToBytes(s.RawString)); err != nil {
-					return nil, err
-				}
-				contentReader = buf
-			}
-
-			if len(s.Data) != 0 {
-				formData := changeMapToURLValues(s.Data)
-				for key, values := range formData {
-					for _, value := range values {
-						fw, _ := mw.CreateFormField(key)
-						if _, err := fw.Write(StringToBytes(value)); err != nil {
-							return nil, err
-						}
-					}
-				}
-				contentReader = buf
-			}
-
-			if len(s.SliceData) != 0 {
-				fieldName := s.Header.Get("json_fieldname")
-				if fieldName == "" {
-					fieldName = "data"
-				}
-				// copied from CreateFormField() in mime/multipart/writer.go
-				h := make(textproto.MIMEHeader)
-				fieldName = strings.Replace(strings.Replace(fieldName, "\\", "\\\\", -1), `"`, "\\\"", -1)
-				h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, fieldName))
-				h.Set("Content-Type", "application/json")
-				fw, _ := mw.CreatePart(h)
-				contentJson, err := json.Marshal(s.SliceData)
-				if err != nil {
-					return nil, err
-				}
-				if _, err := fw.Write(contentJson); err != nil {
-					return nil, err
-				}
-				contentReader = buf
-			}
-
-			// add the files
-			if len(s.FileData) != 0 {
-				for _, file := range s.FileData {
-					fw, _ := CreateFormFile(mw, file.Fieldname, file.Filename, file.MimeType)
-					if _, err := fw.Write(file.Data); err != nil {
-						return nil, err
-					}
-				}
-				contentReader = buf
-			}
-
-			// close before call to FormDataContentType ! otherwise, it's not valid multipart
-			mw.Close()
-
-			if contentReader != nil {
-				contentType = mw.FormDataContentType()
-			}
-		case "":
-			contentType = ""
-			contentReader = nil
-		default:
+	} else if s.TargetType != "" {
+		codec, ok := codecFor(s.TargetType)
+		if !ok {
 			// let's return an error instead of an nil pointer exception here
 			return nil, fmt.Errorf("TargetType '%s' could not be determined", s.TargetType)
 		}
+		body, bodyType, err := codec.Encode(s)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			contentReader = body
+			contentType = bodyType
+			if contentType == "" {
+				contentType = mimeFor(s.TargetType)
+			}
+		}
 	}
 
+	// the readers and files of a streamed body are closed when the request can't be made
+	defer func() {
+		if body, ok := contentReader.(*streamBody); ok && err != nil {
+			body.Close()
+		}
+	}()
+
 	if req, err = http.NewRequest(s.Method, s.Url, contentReader); err != nil {
 		return nil, err
 	}
+	// http.NewRequest only knows the length of in memory bodies
+	if body, ok := contentReader.(*streamBody); ok {
+		req.ContentLength = body.size
+		req.GetBody = body.getBody
+	}
 
 	if s.ctx != nil {
 		req = req.WithContext(s.ctx)
@@ -1029,6 +1068,10 @@ func (s *SuperAgent) MakeRequest() (*http.Request, error) {
 		req.Header.Set("Content-Type", contentType)
 	}
 
+	if s.Retryable.idempotencyKey != "" && req.Header.Get(headerIdempotencyKey) == "" {
+		req.Header.Set(headerIdempotencyKey, s.Retryable.idempotencyKey)
+	}
+
 	// Add all querystring from Query func while preserving caller order.
 	if len(s.QueryParamOrder) != 0 || len(s.QueryData) != 0 {
 		encodedQuery := encodeQueryParams(s.QueryParamOrder, s.QueryData)
@@ -1049,6 +1092,19 @@ func (s *SuperAgent) MakeRequest() (*http.Request, error) {
 		req.AddCookie(cookie)
 	}
 
+	// fail before sending a request which can't be signed
+	if s.signer != nil {
+		if err := s.signer.check(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.authenticator != nil {
+		if err := s.authenticator.Apply(req); err != nil {
+			return nil, err
+		}
+	}
+
 	return req, nil
 }
 
@@ -1098,7 +1154,7 @@ func cloneHttpClient(oldClient *http.Client) *http.Client {
 	return &client
 }
 
-// does a shallow clone of the transport
+// does a shallow clone of the transport, the TLS config is cloned too so changing it doesn't change the original
 func (s *SuperAgent) safeModifyTransport() {
 	if !s.isClone {
 		return
@@ -1110,7 +1166,7 @@ func (s *SuperAgent) safeModifyTransport() {
 		Dial:                  oldTransport.Dial,
 		DialTLSContext:        oldTransport.DialTLSContext,
 		DialTLS:               oldTransport.DialTLS,
-		TLSClientConfig:       oldTransport.TLSClientConfig,
+		TLSClientConfig:       oldTransport.TLSClientConfig.Clone(),
 		TLSHandshakeTimeout:   oldTransport.TLSHandshakeTimeout,
 		DisableKeepAlives:     oldTransport.DisableKeepAlives,
 		DisableCompression:    oldTransport.DisableCompression,
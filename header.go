@@ -50,13 +50,13 @@ func (s *SuperAgent) setHeadersMap(content any) *SuperAgent {
 // Its duty is to transform any (implicitly always a struct) into s.Data (map[string]any) which later changes into appropriate format such as json, form, text, etc. in the End() func.
 func (s *SuperAgent) setHeadersStruct(content any) *SuperAgent {
 	if marshalContent, err := json.Marshal(content); err != nil {
-		s.Errors = append(s.Errors, err)
+		s.appendError(PhaseBuild, err)
 	} else {
 		var val map[string]any
 		d := json.NewDecoder(bytes.NewBuffer(marshalContent))
 		d.UseNumber()
 		if err := d.Decode(&val); err != nil {
-			s.Errors = append(s.Errors, err)
+			s.appendError(PhaseBuild, err)
 		} else {
 			for k, v := range val {
 				strValue, err := cast.ToStringE(v)
@@ -0,0 +1,150 @@
+package gorequest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type hedge struct {
+	delay time.Duration
+	max   int
+}
+
+// Hedge sends another copy of a GET or HEAD request when no response came within delay, up to
+// maxRequests copies in total, to cut the tail latency of replicated services. The first successful
+// response, neither a transport error nor a 5xx, wins and the other copies are cancelled through
+// their contexts.
+// Stats.HedgeWinner is the number of the copy which won.
+// Requests with a body which can't be rewound and other methods are sent once.
+// Example. To send a second request when the first one takes more than 50ms
+//
+//	gorequest.New().
+//	  Get("https://replicas.example.com/items/1").
+//	  Hedge(50*time.Millisecond, 2).
+//	  End()
+func (s *SuperAgent) Hedge(delay time.Duration, maxRequests int) *SuperAgent {
+	s.hedge = hedge{delay: delay, max: maxRequests}
+	return s
+}
+
+type hedgeResult struct {
+	resp   *http.Response
+	err    error
+	copy   int
+	cancel context.CancelFunc
+}
+
+func (s *SuperAgent) hedgeMiddleware(next Handler) Handler {
+	h := s.hedge
+	return func(req *http.Request) (*http.Response, error) {
+		method := strings.ToUpper(req.Method)
+		if h.max <= 1 || (method != GET && method != HEAD) ||
+			(req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			s.Stats.HedgeWinner = 1
+			return next(req)
+		}
+
+		ctx := req.Context()
+		results := make(chan hedgeResult, h.max)
+		launched, pending := 0, 0
+		var cancels []context.CancelFunc
+		launch := func() {
+			launched++
+			pending++
+			copyCtx, cancel := context.WithCancel(ctx)
+			cancels = append(cancels, cancel)
+			r := req.Clone(copyCtx)
+			if launched > 1 && req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					results <- hedgeResult{err: err, copy: launched, cancel: cancel}
+					return
+				}
+				r.Body = body
+			}
+			go func(n int) {
+				resp, err := next(r)
+				results <- hedgeResult{resp: resp, err: err, copy: n, cancel: cancel}
+			}(launched)
+		}
+
+		launch()
+		timer := time.NewTimer(h.delay)
+		defer timer.Stop()
+
+		var failed *hedgeResult
+		for {
+			select {
+			case <-timer.C:
+				if launched < h.max && ctx.Err() == nil {
+					launch()
+					timer.Reset(h.delay)
+				}
+			case result := <-results:
+				pending--
+				if !isFailure(result.resp, result.err) {
+					if failed != nil {
+						discardHedgeResult(*failed)
+					}
+					for i, cancel := range cancels {
+						if i+1 != result.copy {
+							cancel()
+						}
+					}
+					go discardHedgeResults(results, pending)
+					s.Stats.HedgeWinner = result.copy
+					result.resp.Body = &cancelOnClose{ReadCloser: result.resp.Body, cancel: result.cancel}
+					return result.resp, nil
+				}
+				if failed != nil {
+					discardHedgeResult(*failed)
+				}
+				failed = &result
+				if pending > 0 {
+					continue
+				}
+				if launched < h.max && ctx.Err() == nil {
+					launch()
+					timer.Reset(h.delay)
+					continue
+				}
+				s.Stats.HedgeWinner = failed.copy
+				if failed.err != nil {
+					failed.cancel()
+					return nil, failed.err
+				}
+				failed.resp.Body = &cancelOnClose{ReadCloser: failed.resp.Body, cancel: failed.cancel}
+				return failed.resp, nil
+			}
+		}
+	}
+}
+
+// discardHedgeResults closes the responses of the cancelled copies.
+func discardHedgeResults(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		discardHedgeResult(<-results)
+	}
+}
+
+func discardHedgeResult(result hedgeResult) {
+	result.cancel()
+	if result.resp != nil {
+		result.resp.Body.Close()
+	}
+}
+
+// cancelOnClose cancels the context of the request when its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
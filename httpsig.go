@@ -0,0 +1,746 @@
+package gorequest
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMessageSignature is returned, wrapped, by VerifyResponse and VerifyRequest when the message
+// has no valid signature.
+var ErrMessageSignature = errors.New("invalid message signature")
+
+// messageSignatureLabel is the label of the signatures of SignMessage.
+const messageSignatureLabel = "sig1"
+
+// MessageSigner signs HTTP messages for SignMessage, see NewHMACSHA256Signer, NewEd25519Signer,
+// NewECDSAP256Signer and NewRSAPSSSigner.
+type MessageSigner interface {
+	// Algorithm is the name of the algorithm in the HTTP Signature Algorithms registry, such as ed25519.
+	Algorithm() string
+	Sign(base []byte) ([]byte, error)
+}
+
+// MessageVerifier verifies the signatures of HTTP messages for VerifyResponse and VerifyRequest.
+type MessageVerifier interface {
+	Algorithm() string
+	Verify(base []byte, signature []byte) error
+}
+
+type hmacSHA256Key []byte
+
+// NewHMACSHA256Signer returns a MessageSigner for hmac-sha256 with a shared key.
+func NewHMACSHA256Signer(key []byte) MessageSigner {
+	return hmacSHA256Key(key)
+}
+
+// NewHMACSHA256Verifier returns a MessageVerifier for hmac-sha256 with a shared key.
+func NewHMACSHA256Verifier(key []byte) MessageVerifier {
+	return hmacSHA256Key(key)
+}
+
+func (k hmacSHA256Key) Algorithm() string {
+	return "hmac-sha256"
+}
+
+func (k hmacSHA256Key) Sign(base []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, k)
+	mac.Write(base)
+	return mac.Sum(nil), nil
+}
+
+func (k hmacSHA256Key) Verify(base []byte, signature []byte) error {
+	expected, _ := k.Sign(base)
+	if !hmac.Equal(expected, signature) {
+		return errors.New("hmac mismatch")
+	}
+	return nil
+}
+
+type ed25519Signer ed25519.PrivateKey
+
+// NewEd25519Signer returns a MessageSigner for ed25519.
+func NewEd25519Signer(key ed25519.PrivateKey) MessageSigner {
+	return ed25519Signer(key)
+}
+
+func (k ed25519Signer) Algorithm() string {
+	return "ed25519"
+}
+
+func (k ed25519Signer) Sign(base []byte) ([]byte, error) {
+	if len(k) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid ed25519 private key")
+	}
+	return ed25519.Sign(ed25519.PrivateKey(k), base), nil
+}
+
+type ed25519Verifier ed25519.PublicKey
+
+// NewEd25519Verifier returns a MessageVerifier for ed25519.
+func NewEd25519Verifier(key ed25519.PublicKey) MessageVerifier {
+	return ed25519Verifier(key)
+}
+
+func (k ed25519Verifier) Algorithm() string {
+	return "ed25519"
+}
+
+func (k ed25519Verifier) Verify(base []byte, signature []byte) error {
+	if len(k) != ed25519.PublicKeySize || !ed25519.Verify(ed25519.PublicKey(k), base, signature) {
+		return errors.New("ed25519 verification failed")
+	}
+	return nil
+}
+
+type ecdsaP256Signer struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewECDSAP256Signer returns a MessageSigner for ecdsa-p256-sha256, the key must be on the P-256 curve.
+func NewECDSAP256Signer(key *ecdsa.PrivateKey) MessageSigner {
+	return ecdsaP256Signer{key: key}
+}
+
+func (k ecdsaP256Signer) Algorithm() string {
+	return "ecdsa-p256-sha256"
+}
+
+func (k ecdsaP256Signer) Sign(base []byte) ([]byte, error) {
+	if k.key == nil || k.key.Curve.Params().Name != "P-256" {
+		return nil, errors.New("ecdsa-p256-sha256 needs a P-256 private key")
+	}
+	digest := sha256.Sum256(base)
+	r, s, err := ecdsa.Sign(rand.Reader, k.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	// the signature is r and s as 32 bytes big endian integers, RFC 9421 section 3.3.4
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signature, nil
+}
+
+type ecdsaP256Verifier struct {
+	key *ecdsa.PublicKey
+}
+
+// NewECDSAP256Verifier returns a MessageVerifier for ecdsa-p256-sha256.
+func NewECDSAP256Verifier(key *ecdsa.PublicKey) MessageVerifier {
+	return ecdsaP256Verifier{key: key}
+}
+
+func (k ecdsaP256Verifier) Algorithm() string {
+	return "ecdsa-p256-sha256"
+}
+
+func (k ecdsaP256Verifier) Verify(base []byte, signature []byte) error {
+	if k.key == nil || k.key.Curve.Params().Name != "P-256" {
+		return errors.New("ecdsa-p256-sha256 needs a P-256 public key")
+	}
+	if len(signature) != 64 {
+		return errors.New("ecdsa-p256-sha256 signature must be 64 bytes")
+	}
+	digest := sha256.Sum256(base)
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(k.key, digest[:], r, s) {
+		return errors.New("ecdsa verification failed")
+	}
+	return nil
+}
+
+// rsaPSSOptions are the options of rsa-pss-sha512, the salt is 64 bytes, RFC 9421 section 3.3.1.
+var rsaPSSOptions = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA512}
+
+type rsaPSSSigner struct {
+	key *rsa.PrivateKey
+}
+
+// NewRSAPSSSigner returns a MessageSigner for rsa-pss-sha512.
+func NewRSAPSSSigner(key *rsa.PrivateKey) MessageSigner {
+	return rsaPSSSigner{key: key}
+}
+
+func (k rsaPSSSigner) Algorithm() string {
+	return "rsa-pss-sha512"
+}
+
+func (k rsaPSSSigner) Sign(base []byte) ([]byte, error) {
+	digest := sha512.Sum512(base)
+	return rsa.SignPSS(rand.Reader, k.key, crypto.SHA512, digest[:], rsaPSSOptions)
+}
+
+type rsaPSSVerifier struct {
+	key *rsa.PublicKey
+}
+
+// NewRSAPSSVerifier returns a MessageVerifier for rsa-pss-sha512.
+func NewRSAPSSVerifier(key *rsa.PublicKey) MessageVerifier {
+	return rsaPSSVerifier{key: key}
+}
+
+func (k rsaPSSVerifier) Algorithm() string {
+	return "rsa-pss-sha512"
+}
+
+func (k rsaPSSVerifier) Verify(base []byte, signature []byte) error {
+	digest := sha512.Sum512(base)
+	return rsa.VerifyPSS(k.key, crypto.SHA512, digest[:], signature, rsaPSSOptions)
+}
+
+// SignMessage signs the requests with HTTP Message Signatures, RFC 9421, in the Signature-Input and
+// Signature headers. components are the covered components, derived components such as @method,
+// @target-uri, @authority, @path, @query or @query-param;name="id", and lower case header names.
+// They are @method and @target-uri by default. A request with a body gets the Content-Digest header,
+// RFC 9530, which is covered too. A body which can't be read again, from SendReader with a reader
+// which can't seek, is sent without Content-Digest, and the request fails when it's made if
+// content-digest is in components. The request is signed right before it's sent, so again on every
+// attempt and redirect.
+// Example. To sign webhook calls with an Ed25519 key
+//
+//	gorequest.New().
+//	  Post("https://partner.example.com/webhook").
+//	  SignMessage("my-key", gorequest.NewEd25519Signer(privateKey), "@method", "@target-uri", "content-type").
+//	  Send(event).
+//	  End()
+func (s *SuperAgent) SignMessage(keyID string, signer MessageSigner, components ...string) *SuperAgent {
+	if len(components) == 0 {
+		components = []string{"@method", "@target-uri"}
+	}
+	items := make([]sfItem, 0, len(components))
+	for _, component := range components {
+		item, err := parseComponent(component)
+		if err != nil {
+			s.appendError(PhaseBuild, err)
+			return s
+		}
+		items = append(items, item)
+	}
+	s.signer = &messageSigner{keyID: keyID, signer: signer, components: items, now: time.Now}
+	return s
+}
+
+// messageSigner is the requestSigner of SignMessage.
+type messageSigner struct {
+	keyID      string
+	signer     MessageSigner
+	components []sfItem
+	now        func() time.Time
+}
+
+// check returns an error when content-digest is covered but the body can't be read again to hash it.
+func (m *messageSigner) check(req *http.Request) error {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil &&
+		coversComponent(m.components, "content-digest") {
+		return errors.New("content-digest can't be signed, the request body can't be read again")
+	}
+	return nil
+}
+
+func (m *messageSigner) sign(req *http.Request, getBody func() (io.ReadCloser, error)) error {
+	components := m.components
+	if err := m.check(req); err != nil {
+		return err
+	}
+	if req.Body != nil && req.Body != http.NoBody && getBody != nil {
+		h := sha256.New()
+		if err := hashBody(req, getBody, h); err != nil {
+			return err
+		}
+		req.Header.Set("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(h.Sum(nil))+":")
+		if !coversComponent(components, "content-digest") {
+			components = append(append([]sfItem(nil), components...), sfItem{raw: `"content-digest"`, name: "content-digest"})
+		}
+	}
+
+	raw := make([]string, len(components))
+	for i, item := range components {
+		raw[i] = item.raw
+	}
+	params := fmt.Sprintf("(%s);created=%d;keyid=%s;alg=%s", strings.Join(raw, " "), m.now().Unix(),
+		quoteParam(m.keyID), quoteParam(m.signer.Algorithm()))
+	base, err := signatureBase(req, nil, components, params)
+	if err != nil {
+		return err
+	}
+	signature, err := m.signer.Sign(base)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Signature-Input", messageSignatureLabel+"="+params)
+	req.Header.Set("Signature", messageSignatureLabel+"=:"+base64.StdEncoding.EncodeToString(signature)+":")
+	return nil
+}
+
+// VerifyResponse verifies the HTTP Message Signature, RFC 9421, of a response signed by keyID with
+// the algorithm of verifier. The signature must cover the required components, and when it covers
+// Content-Digest the body is checked against it, the body is read and replaced then.
+// Example. To check the signature of a partner API
+//
+//	resp, body, errs := gorequest.New().Get("https://partner.example.com/orders").End()
+//	if len(errs) == 0 {
+//	  err := gorequest.VerifyResponse(resp, "partner-key", gorequest.NewEd25519Verifier(publicKey),
+//	    "@status", "content-digest")
+//	}
+func VerifyResponse(resp *http.Response, keyID string, verifier MessageVerifier, required ...string) error {
+	return verifyMessage(resp.Request, resp, resp.Header, &resp.Body, keyID, verifier, required)
+}
+
+// VerifyRequest verifies the HTTP Message Signature of a request received by a server, such as a
+// webhook call, see VerifyResponse.
+func VerifyRequest(req *http.Request, keyID string, verifier MessageVerifier, required ...string) error {
+	return verifyMessage(req, nil, req.Header, &req.Body, keyID, verifier, required)
+}
+
+func verifyMessage(req *http.Request, resp *http.Response, header http.Header, body *io.ReadCloser,
+	keyID string, verifier MessageVerifier, required []string,
+) error {
+	inputs, err := parseSFDictionary(strings.Join(header.Values("Signature-Input"), ", "))
+	if err != nil {
+		return fmt.Errorf("%w: Signature-Input: %s", ErrMessageSignature, err)
+	}
+	signatures, err := parseSFDictionary(strings.Join(header.Values("Signature"), ", "))
+	if err != nil {
+		return fmt.Errorf("%w: Signature: %s", ErrMessageSignature, err)
+	}
+
+	var errs []error
+	for label, input := range inputs {
+		if input.params["keyid"] != keyID {
+			continue
+		}
+		signature, ok := signatures[label]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no signature for label %s", label))
+			continue
+		}
+		if err := verifySignature(req, resp, input, signature, verifier, required); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", label, err))
+			continue
+		}
+		if coversComponent(input.items, "content-digest") {
+			if err := verifyContentDigest(header, body); err != nil {
+				return fmt.Errorf("%w: %s", ErrMessageSignature, err)
+			}
+		}
+		return nil
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("%w: no signature by key %q", ErrMessageSignature, keyID)
+	}
+	return fmt.Errorf("%w: %w", ErrMessageSignature, joinErrors(errs))
+}
+
+func verifySignature(req *http.Request, resp *http.Response, input, signature sfMember, verifier MessageVerifier,
+	required []string,
+) error {
+	if input.items == nil {
+		return errors.New("signature input is not an inner list")
+	}
+	if alg, ok := input.params["alg"]; ok && alg != verifier.Algorithm() {
+		return fmt.Errorf("algorithm %s, expected %s", alg, verifier.Algorithm())
+	}
+	if expires, ok := input.params["expires"]; ok {
+		if t, err := strconv.ParseInt(expires, 10, 64); err != nil || time.Now().Unix() > t {
+			return errors.New("signature expired")
+		}
+	}
+	for _, component := range required {
+		item, err := parseComponent(component)
+		if err != nil {
+			return err
+		}
+		if !coversComponent(input.items, item.raw) {
+			return fmt.Errorf("component %s is not covered", item.raw)
+		}
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature.value)
+	if err != nil {
+		return errors.New("signature is not a byte sequence")
+	}
+	base, err := signatureBase(req, resp, input.items, input.raw)
+	if err != nil {
+		return err
+	}
+	return verifier.Verify(base, sig)
+}
+
+// verifyContentDigest checks the body against the Content-Digest header, RFC 9530.
+func verifyContentDigest(header http.Header, body *io.ReadCloser) error {
+	digests, err := parseSFDictionary(strings.Join(header.Values("Content-Digest"), ", "))
+	if err != nil {
+		return fmt.Errorf("invalid content digest: %w", err)
+	}
+	var content []byte
+	if *body != nil && *body != http.NoBody {
+		content, err = io.ReadAll(*body)
+		(*body).Close()
+		*body = io.NopCloser(bytes.NewReader(content))
+		if err != nil {
+			return err
+		}
+	}
+
+	checked := false
+	for _, algorithm := range []struct {
+		name string
+		hash func() hash.Hash
+	}{{"sha-256", sha256.New}, {"sha-512", sha512.New}} {
+		digest, ok := digests[algorithm.name]
+		if !ok {
+			continue
+		}
+		h := algorithm.hash()
+		h.Write(content)
+		if digest.value != base64.StdEncoding.EncodeToString(h.Sum(nil)) {
+			return fmt.Errorf("content digest %s mismatch", algorithm.name)
+		}
+		checked = true
+	}
+	if !checked {
+		return errors.New("no supported content digest algorithm")
+	}
+	return nil
+}
+
+func coversComponent(items []sfItem, name string) bool {
+	if !strings.HasPrefix(name, `"`) {
+		name = `"` + name + `"`
+	}
+	for _, item := range items {
+		if item.raw == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseComponent parses a component identifier such as content-type or @query-param;name="id".
+func parseComponent(component string) (sfItem, error) {
+	if !strings.HasPrefix(component, `"`) {
+		name, params, _ := strings.Cut(component, ";")
+		component = quoteParam(strings.ToLower(name))
+		if params != "" {
+			component += ";" + params
+		}
+	}
+	p := &sfParser{s: component}
+	item, err := p.item()
+	if err == nil && p.pos != len(p.s) {
+		err = fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+	if err != nil {
+		return sfItem{}, fmt.Errorf("invalid component %q: %w", component, err)
+	}
+	return item, nil
+}
+
+// signatureBase returns the signature base of RFC 9421 section 2.5, resp is nil for a request.
+func signatureBase(req *http.Request, resp *http.Response, items []sfItem, signatureParams string) ([]byte, error) {
+	var b bytes.Buffer
+	seen := map[string]bool{}
+	for _, item := range items {
+		if seen[item.raw] {
+			return nil, fmt.Errorf("component %s is covered twice", item.raw)
+		}
+		seen[item.raw] = true
+		value, err := componentValue(req, resp, item)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(item.raw + ": " + value + "\n")
+	}
+	b.WriteString(`"@signature-params": ` + signatureParams)
+	return b.Bytes(), nil
+}
+
+// componentValue returns the value of a covered component, RFC 9421 section 2.
+func componentValue(req *http.Request, resp *http.Response, item sfItem) (string, error) {
+	if _, ok := item.params["req"]; ok {
+		if resp == nil {
+			return "", fmt.Errorf("component %s: req is only allowed in responses", item.raw)
+		}
+		resp = nil
+	}
+	if resp == nil && req == nil {
+		return "", fmt.Errorf("component %s: no request", item.raw)
+	}
+
+	if !strings.HasPrefix(item.name, "@") {
+		for name := range item.params {
+			if name != "req" {
+				return "", fmt.Errorf("component %s: parameter %s is not supported", item.raw, name)
+			}
+		}
+		header := req.Header
+		if resp != nil {
+			header = resp.Header
+		}
+		values := header.Values(item.name)
+		if len(values) == 0 {
+			return "", fmt.Errorf("component %s is not in the message", item.raw)
+		}
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.TrimSpace(value)
+		}
+		return strings.Join(trimmed, ", "), nil
+	}
+
+	if item.name == "@status" {
+		if resp == nil {
+			return "", fmt.Errorf("component %s is only allowed in responses", item.raw)
+		}
+		return strconv.Itoa(resp.StatusCode), nil
+	}
+	if resp != nil {
+		return "", fmt.Errorf("component %s needs the req parameter in responses", item.raw)
+	}
+
+	u := *req.URL
+	if req.Host != "" {
+		u.Host = req.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if req.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	switch item.name {
+	case "@method":
+		return req.Method, nil
+	case "@target-uri":
+		return u.Scheme + "://" + u.Host + u.RequestURI(), nil
+	case "@authority":
+		host := strings.ToLower(u.Host)
+		if strings.EqualFold(u.Scheme, "http") {
+			host = strings.TrimSuffix(host, ":80")
+		} else if strings.EqualFold(u.Scheme, "https") {
+			host = strings.TrimSuffix(host, ":443")
+		}
+		return host, nil
+	case "@scheme":
+		return strings.ToLower(u.Scheme), nil
+	case "@request-target":
+		return u.RequestURI(), nil
+	case "@path":
+		if path := u.EscapedPath(); path != "" {
+			return path, nil
+		}
+		return "/", nil
+	case "@query":
+		return "?" + u.RawQuery, nil
+	case "@query-param":
+		name, ok := item.params["name"]
+		if !ok {
+			return "", fmt.Errorf("component %s needs the name parameter", item.raw)
+		}
+		values := u.Query()[name]
+		if len(values) != 1 {
+			return "", fmt.Errorf("component %s: %d values in the query", item.raw, len(values))
+		}
+		return strings.ReplaceAll(url.QueryEscape(values[0]), "+", "%20"), nil
+	}
+	return "", fmt.Errorf("component %s is not supported", item.raw)
+}
+
+// sfItem is an item of an inner list of a structured field, RFC 8941, such as a component identifier.
+type sfItem struct {
+	// raw is the item as serialized, with its parameters.
+	raw    string
+	name   string
+	params map[string]string
+}
+
+// sfMember is a member of a structured field dictionary.
+type sfMember struct {
+	// raw is the member value as serialized, with its parameters.
+	raw string
+	// items are the items of an inner list, nil for an item.
+	items []sfItem
+	// value is the value of an item, strings are unquoted and byte sequences are base64.
+	value  string
+	params map[string]string
+}
+
+// sfParser parses the structured fields used by HTTP Message Signatures and Content-Digest.
+type sfParser struct {
+	s   string
+	pos int
+}
+
+func parseSFDictionary(value string) (map[string]sfMember, error) {
+	p := &sfParser{s: value}
+	members := map[string]sfMember{}
+	for {
+		p.skip(" \t")
+		if p.pos == len(p.s) {
+			return members, nil
+		}
+		key := p.key()
+		if key == "" {
+			return nil, fmt.Errorf("invalid key at %d", p.pos)
+		}
+		var member sfMember
+		if p.next('=') {
+			start := p.pos
+			if p.next('(') {
+				member.items = []sfItem{}
+				for {
+					p.skip(" ")
+					if p.next(')') {
+						break
+					}
+					item, err := p.item()
+					if err != nil {
+						return nil, err
+					}
+					member.items = append(member.items, item)
+				}
+			} else {
+				value, err := p.bareItem()
+				if err != nil {
+					return nil, err
+				}
+				member.value = value
+			}
+			params, err := p.params()
+			if err != nil {
+				return nil, err
+			}
+			member.params = params
+			member.raw = p.s[start:p.pos]
+		}
+		members[key] = member
+
+		p.skip(" \t")
+		if p.pos == len(p.s) {
+			return members, nil
+		}
+		if !p.next(',') {
+			return nil, fmt.Errorf("expected a comma at %d", p.pos)
+		}
+	}
+}
+
+func (p *sfParser) item() (sfItem, error) {
+	start := p.pos
+	name, err := p.bareItem()
+	if err != nil {
+		return sfItem{}, err
+	}
+	params, err := p.params()
+	if err != nil {
+		return sfItem{}, err
+	}
+	return sfItem{raw: p.s[start:p.pos], name: name, params: params}, nil
+}
+
+func (p *sfParser) params() (map[string]string, error) {
+	params := map[string]string{}
+	for p.next(';') {
+		p.skip(" ")
+		key := p.key()
+		if key == "" {
+			return nil, fmt.Errorf("invalid parameter at %d", p.pos)
+		}
+		params[key] = "?1"
+		if p.next('=') {
+			value, err := p.bareItem()
+			if err != nil {
+				return nil, err
+			}
+			params[key] = value
+		}
+	}
+	return params, nil
+}
+
+func (p *sfParser) bareItem() (string, error) {
+	if p.pos == len(p.s) {
+		return "", errors.New("unexpected end")
+	}
+	switch p.s[p.pos] {
+	case '"':
+		var b strings.Builder
+		for p.pos++; p.pos < len(p.s); p.pos++ {
+			switch c := p.s[p.pos]; c {
+			case '\\':
+				p.pos++
+				if p.pos == len(p.s) {
+					return "", errors.New("unterminated string")
+				}
+				b.WriteByte(p.s[p.pos])
+			case '"':
+				p.pos++
+				return b.String(), nil
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", errors.New("unterminated string")
+	case ':':
+		end := strings.IndexByte(p.s[p.pos+1:], ':')
+		if end < 0 {
+			return "", errors.New("unterminated byte sequence")
+		}
+		value := p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return value, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(" \t;,()=\"", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("invalid item at %d", p.pos)
+	}
+	return p.s[start:p.pos], nil
+}
+
+func (p *sfParser) key() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_' || c == '-' || c == '.' || c == '*' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *sfParser) next(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sfParser) skip(chars string) {
+	for p.pos < len(p.s) && strings.IndexByte(chars, p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
@@ -0,0 +1,76 @@
+package gorequest
+
+import "net/http"
+
+// Handler sends a built request and returns the response.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the Handler which sends the request, it can inspect or modify the
+// request before calling next and inspect the response or error afterwards.
+type Middleware func(next Handler) Handler
+
+// Use registers middlewares around the call to the http.Client.
+// Middlewares run in the order they are registered, the first one is the outermost,
+// and they run once per attempt when Retry is enabled.
+// Example. To add a header to every request and log the status code
+//
+//	gorequest.New().
+//	  Use(func(next gorequest.Handler) gorequest.Handler {
+//	    return func(req *http.Request) (*http.Response, error) {
+//	      req.Header.Set("X-Request-Id", "123")
+//	      resp, err := next(req)
+//	      if err == nil {
+//	        log.Println(resp.StatusCode)
+//	      }
+//	      return resp, err
+//	    }
+//	  }).
+//	  Get("https://httpbin.org/get").
+//	  End()
+func (s *SuperAgent) Use(middlewares ...Middleware) *SuperAgent {
+	for _, m := range middlewares {
+		if m != nil {
+			s.middlewares = append(s.middlewares, m)
+		}
+	}
+	return s
+}
+
+// handler builds the middleware chain around the http.Client, the built-in stages such as
+// Cache, the circuit breaker, the rate limiter, the concurrency limit, the 401 replay of the
+// Authenticator and Hedge run inside the middlewares registered with Use. Requests are signed last,
+// right before they are sent, and then their body is wrapped for the upload progress.
+func (s *SuperAgent) handler() Handler {
+	h := s.clientDo(s.signer)
+	if s.hedge.max > 1 {
+		h = s.hedgeMiddleware(h)
+	}
+	if s.authenticator != nil {
+		h = s.authMiddleware(h)
+	}
+	if s.bulkhead != nil {
+		h = s.bulkheadMiddleware(h)
+	}
+	if s.limiter != nil {
+		h = s.rateLimitMiddleware(h)
+	}
+	if s.breaker != nil {
+		h = s.breaker.middleware(h)
+	}
+	if s.cache != nil {
+		h = s.cacheMiddleware(h)
+	}
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		h = s.middlewares[i](h)
+	}
+	return h
+}
+
+func shallowCopyMiddlewares(old []Middleware) []Middleware {
+	if old == nil {
+		return nil
+	}
+	newData := make([]Middleware, len(old))
+	copy(newData, old)
+	return newData
+}
@@ -0,0 +1,327 @@
+package gorequest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// grantTypeDeviceCode is the grant type of the device access token request, RFC 8628 section 3.4.
+const grantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// defaultDeviceInterval is the polling interval of the device flow when the server doesn't give one,
+// and slowDownInterval is added to it on slow_down, RFC 8628 section 3.5.
+const (
+	defaultDeviceInterval = 5 * time.Second
+	slowDownInterval      = 5 * time.Second
+)
+
+// OAuth2Config is an OAuth2 client and the endpoints of its authorization server, it gets tokens with
+// the client credentials, refresh token and device authorization grants. Its token sources are used
+// with NewBearerAuth, which caches the tokens and applies them to the requests.
+//
+//	cfg := &gorequest.OAuth2Config{
+//	  ClientID:     "my-client",
+//	  ClientSecret: "my-secret",
+//	  TokenURL:     "https://auth.example.com/oauth/token",
+//	  Scopes:       []string{"orders:read"},
+//	}
+//	base := gorequest.New().SetAuthenticator(gorequest.NewBearerAuth(cfg.ClientCredentials()))
+//	resp, body, errs := base.Clone().Get("https://api.example.com/orders").End()
+type OAuth2Config struct {
+	ClientID     string
+	ClientSecret string
+	// TokenURL is the token endpoint, DeviceAuthURL the device authorization endpoint.
+	TokenURL      string
+	DeviceAuthURL string
+	Scopes        []string
+	// AuthInBody sends the client credentials in the request body instead of the Authorization header.
+	// The client id is always sent in the body when there is no ClientSecret.
+	AuthInBody bool
+	// Agent is the SuperAgent the token requests are sent with, for its timeout, TLS or retry settings.
+	// It's cloned for each request, nil means New().
+	Agent *SuperAgent
+}
+
+// OAuth2Error is an error response of an authorization server, RFC 6749 section 5.2.
+type OAuth2Error struct {
+	StatusCode int
+	// Code is the error code, such as invalid_client or authorization_pending.
+	Code        string
+	Description string
+	URI         string
+}
+
+func (e *OAuth2Error) Error() string {
+	if e.Description == "" {
+		return "oauth2: " + e.Code
+	}
+	return fmt.Sprintf("oauth2: %s: %s", e.Code, e.Description)
+}
+
+// DeviceAuthorization is the response of the device authorization endpoint, RFC 8628 section 3.2.
+// Show the UserCode and the VerificationURI to the user, then call DeviceAccessToken.
+type DeviceAuthorization struct {
+	DeviceCode              string
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	// Expiry is when the device code expires, the zero value means it doesn't say.
+	Expiry time.Time
+	// Interval is how long to wait between polls of the token endpoint.
+	Interval time.Duration
+}
+
+// ClientCredentials returns a TokenSource getting tokens with the client credentials grant.
+func (c *OAuth2Config) ClientCredentials() TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		values := url.Values{"grant_type": {"client_credentials"}}
+		c.setScope(values)
+		return c.retrieveToken(ctx, values)
+	})
+}
+
+// TokenSource returns a TokenSource reusing token until it expires, then getting new tokens with the
+// refresh token grant. The refresh token is replaced when the server sends a new one. A token rejected
+// with 401 Unauthorized by a BearerAuth is refreshed before it expires.
+// Use &Token{RefreshToken: refreshToken} to start from a refresh token only.
+func (c *OAuth2Config) TokenSource(token *Token) TokenSource {
+	return &refreshTokenSource{config: c, token: token}
+}
+
+// refreshTokenSource is the TokenSource of OAuth2Config.TokenSource.
+type refreshTokenSource struct {
+	config *OAuth2Config
+
+	mu    sync.Mutex
+	token *Token
+}
+
+func (r *refreshTokenSource) Token(ctx context.Context) (*Token, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token.valid(defaultExpiryDelta) {
+		return r.token, nil
+	}
+	if r.token == nil || r.token.RefreshToken == "" {
+		return nil, errors.New("oauth2: token expired and no refresh token")
+	}
+	token, err := r.config.retrieveToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {r.token.RefreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = r.token.RefreshToken
+	}
+	r.token = token
+	return token, nil
+}
+
+// Invalidate drops the access token when it's the current one, the next call to Token refreshes it.
+func (r *refreshTokenSource) Invalidate(token *Token) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != nil && token != nil && r.token.AccessToken == token.AccessToken {
+		r.token = &Token{RefreshToken: r.token.RefreshToken}
+	}
+}
+
+// DeviceAuth starts the device authorization grant, RFC 8628.
+//
+//	da, err := cfg.DeviceAuth(ctx)
+//	if err != nil {
+//	  return err
+//	}
+//	fmt.Printf("Open %s and enter %s\n", da.VerificationURI, da.UserCode)
+//	token, err := cfg.DeviceAccessToken(ctx, da)
+func (c *OAuth2Config) DeviceAuth(ctx context.Context) (*DeviceAuthorization, error) {
+	values := url.Values{}
+	c.setScope(values)
+	resp, body, err := c.post(ctx, c.DeviceAuthURL, values)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, oauth2ResponseError(resp, body)
+	}
+
+	var data struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               any    `json:"expires_in"`
+		Interval                any    `json:"interval"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("oauth2: cannot parse device authorization response: %w", err)
+	}
+	if data.DeviceCode == "" {
+		return nil, errors.New("oauth2: device authorization response has no device_code")
+	}
+	da := &DeviceAuthorization{
+		DeviceCode:              data.DeviceCode,
+		UserCode:                data.UserCode,
+		VerificationURI:         data.VerificationURI,
+		VerificationURIComplete: data.VerificationURIComplete,
+		Interval:                defaultDeviceInterval,
+	}
+	if expiresIn := cast.ToInt64(data.ExpiresIn); expiresIn > 0 {
+		da.Expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	if interval := cast.ToInt64(data.Interval); interval > 0 {
+		da.Interval = time.Duration(interval) * time.Second
+	}
+	return da, nil
+}
+
+// DeviceAccessToken polls the token endpoint until the user approves or denies the device authorization,
+// the device code expires or ctx is done. It waits Interval between polls and slows down when asked to.
+// A denial is an *OAuth2Error with the Code access_denied.
+func (c *OAuth2Config) DeviceAccessToken(ctx context.Context, da *DeviceAuthorization) (*Token, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	interval := da.Interval
+	if interval <= 0 {
+		interval = defaultDeviceInterval
+	}
+	values := url.Values{
+		"grant_type":  {grantTypeDeviceCode},
+		"device_code": {da.DeviceCode},
+	}
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if !da.Expiry.IsZero() && time.Now().After(da.Expiry) {
+			return nil, &OAuth2Error{Code: "expired_token", Description: "the device code expired"}
+		}
+
+		token, err := c.retrieveToken(ctx, values)
+		var oauthErr *OAuth2Error
+		if !errors.As(err, &oauthErr) {
+			return token, err
+		}
+		switch oauthErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += slowDownInterval
+		default:
+			return nil, err
+		}
+	}
+}
+
+func (c *OAuth2Config) setScope(values url.Values) {
+	if len(c.Scopes) != 0 {
+		values.Set("scope", strings.Join(c.Scopes, " "))
+	}
+}
+
+// retrieveToken sends a token request, RFC 6749 section 4.
+func (c *OAuth2Config) retrieveToken(ctx context.Context, values url.Values) (*Token, error) {
+	resp, body, err := c.post(ctx, c.TokenURL, values)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, oauth2ResponseError(resp, body)
+	}
+
+	var data struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    any    `json:"expires_in"`
+		Error        string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("oauth2: cannot parse token response: %w", err)
+	}
+	if data.Error != "" {
+		return nil, oauth2ResponseError(resp, body)
+	}
+	if data.AccessToken == "" {
+		return nil, errors.New("oauth2: token response has no access_token")
+	}
+	token := &Token{
+		AccessToken:  data.AccessToken,
+		TokenType:    data.TokenType,
+		RefreshToken: data.RefreshToken,
+	}
+	if expiresIn := cast.ToInt64(data.ExpiresIn); expiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// post sends a form to an endpoint of the authorization server with the client authentication,
+// RFC 6749 section 2.3.1.
+func (c *OAuth2Config) post(ctx context.Context, endpoint string, values url.Values) (Response, []byte, error) {
+	agent := New()
+	if c.Agent != nil {
+		agent = c.Agent.Clone()
+	}
+	agent.Post(endpoint).Type(TypeForm).Set("Accept", "application/json")
+	if ctx != nil {
+		agent.Context(ctx)
+	}
+
+	if c.ClientSecret == "" || c.AuthInBody {
+		values.Set("client_id", c.ClientID)
+		if c.ClientSecret != "" {
+			values.Set("client_secret", c.ClientSecret)
+		}
+	} else {
+		agent.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	resp, body, errs := agent.SendString(values.Encode()).EndBytes()
+	if len(errs) != 0 {
+		return nil, nil, joinErrors(errs)
+	}
+	return resp, body, nil
+}
+
+// oauth2ResponseError returns an *OAuth2Error for an error response, or an *HTTPError when the body
+// is not an OAuth2 error.
+func oauth2ResponseError(resp Response, body []byte) error {
+	var data struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+		ErrorURI         string `json:"error_uri"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil || data.Error == "" {
+		if len(body) > maxHTTPErrorBody {
+			body = body[:maxHTTPErrorBody]
+		}
+		return &HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header.Clone(),
+			Body:       shallowCopyBytes(body),
+		}
+	}
+	return &OAuth2Error{
+		StatusCode:  resp.StatusCode,
+		Code:        data.Error,
+		Description: data.ErrorDescription,
+		URI:         data.ErrorURI,
+	}
+}
@@ -3,55 +3,157 @@ package gorequest
 import (
 	"io"
 	"net/http"
+	"time"
 )
 
 // UploadProgress receives the cumulative number of request body bytes uploaded.
 type UploadProgress func(uploaded int64)
 
+// DownloadProgress receives the number of response body bytes downloaded and the size of the body,
+// total is -1 when the response has no Content-Length.
+type DownloadProgress func(downloaded, total int64)
+
+// Progress describes a transfer for the detailed progress callbacks.
+type Progress struct {
+	// Transferred is the number of bytes transferred, Total the size of the body or -1 when unknown.
+	Transferred int64
+	Total       int64
+	// Rate is the average transfer rate in bytes per second.
+	Rate float64
+	// ETA is the estimated time left, -1 when Total or Rate is unknown.
+	ETA time.Duration
+}
+
 // SetUploadProgress sets a callback for request body upload progress.
 func (s *SuperAgent) SetUploadProgress(progress UploadProgress) *SuperAgent {
 	s.uploadProgress = progress
 	return s
 }
 
-func (s *SuperAgent) wrapUploadProgress(req *http.Request) {
-	if s.uploadProgress == nil || req.Body == nil {
-		return
+// SetUploadProgressDetail sets a callback for request body upload progress with the size of the body,
+// the transfer rate and the ETA. The count starts again when the body is sent again on a redirect or a retry.
+func (s *SuperAgent) SetUploadProgressDetail(progress func(Progress)) *SuperAgent {
+	s.uploadDetail = progress
+	return s
+}
+
+// SetDownloadProgress sets a callback for response body download progress, it's called as the body
+// is read, by the End functions or by the caller of EndStream.
+//
+//	gorequest.New().
+//	  Get("https://example.com/large.iso").
+//	  SetDownloadProgress(func(downloaded, total int64) {
+//	    fmt.Printf("%d / %d\n", downloaded, total)
+//	  }).
+//	  EndToFile("./large.iso", gorequest.DownloadOptions{})
+func (s *SuperAgent) SetDownloadProgress(progress DownloadProgress) *SuperAgent {
+	s.downloadProgress = progress
+	return s
+}
+
+// SetDownloadProgressDetail sets a callback for response body download progress with the transfer rate
+// and the ETA, see SetDownloadProgress.
+func (s *SuperAgent) SetDownloadProgressDetail(progress func(Progress)) *SuperAgent {
+	s.downloadDetail = progress
+	return s
+}
+
+// progressTracker computes the Progress of a transfer.
+type progressTracker struct {
+	total       int64
+	start       time.Time
+	transferred int64
+}
+
+func newProgressTracker(total int64) *progressTracker {
+	if total <= 0 {
+		total = -1
+	}
+	return &progressTracker{total: total, start: time.Now()}
+}
+
+func (p *progressTracker) add(n int) Progress {
+	p.transferred += int64(n)
+	progress := Progress{Transferred: p.transferred, Total: p.total, ETA: -1}
+	if elapsed := time.Since(p.start).Seconds(); elapsed > 0 {
+		progress.Rate = float64(p.transferred) / elapsed
+	}
+	if p.total >= 0 && progress.Rate > 0 {
+		progress.ETA = time.Duration(float64(p.total-p.transferred) / progress.Rate * float64(time.Second))
+	}
+	return progress
+}
+
+// wrapUploadProgress returns a copy of req whose body reports the upload progress, the body of req
+// is left as is so it can be read again without reporting it, to be hashed or replayed.
+func (s *SuperAgent) wrapUploadProgress(req *http.Request) *http.Request {
+	if (s.uploadProgress == nil && s.uploadDetail == nil) || req.Body == nil || req.Body == http.NoBody {
+		return req
 	}
 
 	var uploaded int64
+	tracker := newProgressTracker(req.ContentLength)
 	report := func(n int) {
 		uploaded += int64(n)
-		s.uploadProgress(uploaded)
+		if s.uploadProgress != nil {
+			s.uploadProgress(uploaded)
+		}
+		if s.uploadDetail != nil {
+			s.uploadDetail(tracker.add(n))
+		}
 	}
 
-	req.Body = &uploadProgressReadCloser{
+	wrapped := new(http.Request)
+	*wrapped = *req
+	wrapped.Body = &progressReadCloser{
 		ReadCloser: req.Body,
 		report:     report,
 	}
 
 	if req.GetBody == nil {
-		return
+		return wrapped
 	}
 	getBody := req.GetBody
-	req.GetBody = func() (io.ReadCloser, error) {
+	wrapped.GetBody = func() (io.ReadCloser, error) {
 		body, err := getBody()
 		if err != nil {
 			return nil, err
 		}
-		return &uploadProgressReadCloser{
+		tracker = newProgressTracker(req.ContentLength)
+		return &progressReadCloser{
 			ReadCloser: body,
 			report:     report,
 		}, nil
 	}
+	return wrapped
+}
+
+func (s *SuperAgent) wrapDownloadProgress(resp *http.Response) {
+	if (s.downloadProgress == nil && s.downloadDetail == nil) || resp.Body == nil || resp.Body == http.NoBody {
+		return
+	}
+
+	tracker := newProgressTracker(resp.ContentLength)
+	resp.Body = &progressReadCloser{
+		ReadCloser: resp.Body,
+		report: func(n int) {
+			progress := tracker.add(n)
+			if s.downloadProgress != nil {
+				s.downloadProgress(progress.Transferred, progress.Total)
+			}
+			if s.downloadDetail != nil {
+				s.downloadDetail(progress)
+			}
+		},
+	}
 }
 
-type uploadProgressReadCloser struct {
+type progressReadCloser struct {
 	io.ReadCloser
 	report func(int)
 }
 
-func (r *uploadProgressReadCloser) Read(p []byte) (int, error) {
+func (r *progressReadCloser) Read(p []byte) (int, error) {
 	n, err := r.ReadCloser.Read(p)
 	if n > 0 {
 		r.report(n)
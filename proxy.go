@@ -29,7 +29,7 @@ import (
 func (s *SuperAgent) Proxy(proxyUrl string) *SuperAgent {
 	parsedProxyUrl, err := url.Parse(proxyUrl)
 	if err != nil {
-		s.Errors = append(s.Errors, err)
+		s.appendError(PhaseBuild, err)
 	} else if proxyUrl == "" {
 		s.safeModifyTransport()
 		s.Transport.Proxy = nil
@@ -38,7 +38,7 @@ func (s *SuperAgent) Proxy(proxyUrl string) *SuperAgent {
 		s.safeModifyTransport()
 		dialContext, err := socks5DialContext(parsedProxyUrl)
 		if err != nil {
-			s.Errors = append(s.Errors, err)
+			s.appendError(PhaseBuild, err)
 			return s
 		}
 		s.Transport.Proxy = nil
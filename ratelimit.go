@@ -0,0 +1,166 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitWait is returned, wrapped with the context error, when the wait of RateLimit for a token
+// is aborted because the context is done, check it with errors.Is.
+var ErrRateLimitWait = errors.New("rate limit wait")
+
+// rateLimiter holds a token bucket per host, it's shared by the clones of a SuperAgent.
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   rateLimit            // limit of the hosts without an override
+	hosts   map[string]rateLimit // per host overrides
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+type rateLimit struct {
+	rps   float64 // 0 means no limit
+	burst int
+}
+
+type tokenBucket struct {
+	limit  rateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		hosts:   make(map[string]rateLimit),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func newRateLimit(rps float64, burst int) rateLimit {
+	if rps < 0 || math.IsNaN(rps) {
+		rps = 0
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return rateLimit{rps: rps, burst: burst}
+}
+
+// RateLimit limits the requests to rps requests per second for each host, with bursts of up to
+// burst requests, rps 0 means no limit. Requests over the limit wait for their turn, the wait is
+// aborted when the context given to Context is done and its duration is recorded in Stats.RateLimitWait.
+// The token buckets are shared by the clones of the SuperAgent made after this call,
+// so the limit holds for all the goroutines using them.
+// Example. To send at most 10 requests per second, and 2 per second to api.example.com
+//
+//	base := gorequest.New().
+//	  RateLimit(10, 5).
+//	  RateLimitHost("api.example.com", 2, 1)
+//	resp, body, errs := base.Clone().Get("https://api.example.com/items").End()
+func (s *SuperAgent) RateLimit(rps float64, burst int) *SuperAgent {
+	limiter := newRateLimiter()
+	limiter.limit = newRateLimit(rps, burst)
+	if s.limiter != nil {
+		s.limiter.mu.Lock()
+		for host, limit := range s.limiter.hosts {
+			limiter.hosts[host] = limit
+		}
+		s.limiter.mu.Unlock()
+	}
+	s.limiter = limiter
+	return s
+}
+
+// RateLimitHost overrides the limit of RateLimit for host, which is the host of the request url,
+// including the port when there is one. It changes the limiter shared with the clones of the SuperAgent.
+func (s *SuperAgent) RateLimitHost(host string, rps float64, burst int) *SuperAgent {
+	if s.limiter == nil {
+		s.limiter = newRateLimiter()
+	}
+	s.limiter.mu.Lock()
+	s.limiter.hosts[host] = newRateLimit(rps, burst)
+	delete(s.limiter.buckets, host)
+	s.limiter.mu.Unlock()
+	return s
+}
+
+func (s *SuperAgent) rateLimitMiddleware(next Handler) Handler {
+	limiter := s.limiter
+	return func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		err := limiter.wait(req.Context(), req.URL.Host)
+		s.Stats.RateLimitWait = time.Since(start)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRateLimitWait, err)
+		}
+		return next(req)
+	}
+}
+
+// wait takes a token from the bucket of host, waiting until there is one.
+func (l *rateLimiter) wait(ctx context.Context, host string) error {
+	delay, ok := l.reserve(host)
+	if !ok || delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel(host)
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it, ok is false without limit.
+func (l *rateLimiter) reserve(host string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, ok := l.hosts[host]
+	if !ok {
+		limit = l.limit
+	}
+	if limit.rps == 0 {
+		return 0, false
+	}
+
+	now := l.now()
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &tokenBucket{limit: limit, tokens: float64(limit.burst), last: now}
+		l.buckets[host] = b
+	}
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0, true
+	}
+	return time.Duration(-b.tokens / b.limit.rps * float64(time.Second)), true
+}
+
+// cancel gives back the token of a request which stopped waiting.
+func (l *rateLimiter) cancel(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[host]; ok {
+		b.refill(l.now())
+		b.tokens = math.Min(b.tokens+1, float64(b.limit.burst))
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.tokens+elapsed.Seconds()*b.limit.rps, float64(b.limit.burst))
+		b.last = now
+	}
+}
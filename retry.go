@@ -1,18 +1,60 @@
 package gorequest
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
+	mathrand "math/rand"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
+const headerIdempotencyKey = "Idempotency-Key"
+
 type superAgentRetryable struct {
 	RetryableStatus []int
 	RetryerTime     time.Duration
 	RetryerCount    int
 	Attempt         int
 	Enable          bool
+	Policy          RetryPolicy
+	// NonIdempotent allows retrying methods which are not idempotent, such as POST or PATCH.
+	NonIdempotent bool
+
+	startTime      time.Time
+	prevDelay      time.Duration
+	idempotencyKey string
+}
+
+// Backoff is the strategy computing the delay before each retry.
+type Backoff int
+
+const (
+	// BackoffConstant waits BaseDelay before every retry.
+	BackoffConstant Backoff = iota
+	// BackoffExponential waits BaseDelay, then doubles the delay before every retry.
+	BackoffExponential
+	// BackoffDecorrelatedJitter waits a random delay between BaseDelay and three times the previous delay,
+	// so that clients retrying at the same time spread out.
+	BackoffDecorrelatedJitter
+)
+
+// RetryPolicy controls the delay between the attempts of Retry.
+type RetryPolicy struct {
+	Backoff Backoff
+	// BaseDelay is the first delay, the retryerTime given to Retry is used when it's 0.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed delay, 0 means no cap.
+	MaxDelay time.Duration
+	// MaxElapsedTime stops retrying when the next attempt would start after this time
+	// since the first attempt, 0 means no limit.
+	MaxElapsedTime time.Duration
+	// RespectRetryAfter waits for the Retry-After header of the response instead of the computed
+	// delay when it's present, e.g. on 429 or 503. It's not capped by MaxDelay.
+	RespectRetryAfter bool
 }
 
 // Retry is used for setting a Retryer policy
@@ -29,34 +71,220 @@ func (s *SuperAgent) Retry(retryerCount int, retryerTime time.Duration, statusCo
 	for _, code := range statusCode {
 		statusText := http.StatusText(code)
 		if len(statusText) == 0 {
-			s.Errors = append(s.Errors, fmt.Errorf("StatusCode '%d' doesn't exist in http package", code))
+			s.appendError(PhaseBuild, fmt.Errorf("StatusCode '%d' doesn't exist in http package", code))
 		}
 	}
 
-	s.Retryable = struct {
-		RetryableStatus []int
-		RetryerTime     time.Duration
-		RetryerCount    int
-		Attempt         int
-		Enable          bool
-	}{
-		statusCode,
-		retryerTime,
-		retryerCount,
-		0,
-		true,
+	s.Retryable = superAgentRetryable{
+		RetryableStatus: statusCode,
+		RetryerTime:     retryerTime,
+		RetryerCount:    retryerCount,
+		Attempt:         0,
+		Enable:          true,
+		Policy:          s.Retryable.Policy,
+		NonIdempotent:   s.Retryable.NonIdempotent,
 	}
 	return s
 }
 
-func (s *SuperAgent) shouldRetry(resp Response, hasError bool) bool {
-	if s.Retryable.Enable && s.Retryable.Attempt < s.Retryable.RetryerCount &&
-		(hasError || statusesContains(s.Retryable.RetryableStatus, resp.StatusCode)) {
-		time.Sleep(s.Retryable.RetryerTime)
-		s.Retryable.Attempt++
+// RetryNonIdempotent allows Retry to retry methods which are not idempotent, such as POST or PATCH.
+// By default only GET, HEAD, OPTIONS, TRACE, PUT and DELETE requests, and requests with an
+// Idempotency-Key header, are retried, as retrying others may apply them twice, e.g. create two orders.
+func (s *SuperAgent) RetryNonIdempotent() *SuperAgent {
+	s.Retryable.NonIdempotent = true
+	return s
+}
+
+// IdempotencyKey sets a generated Idempotency-Key header on the request, the same key is sent
+// on every attempt of Retry so the server can recognize them as one request, and a new key is
+// generated for the next request. Requests with the header are retried whatever the method is.
+// An Idempotency-Key header set with Set is used instead of a generated one.
+//
+//	gorequest.New().
+//	  Post("https://example.com/orders").
+//	  IdempotencyKey().
+//	  Send(order).
+//	  Retry(3, time.Second, http.StatusServiceUnavailable).
+//	  End()
+func (s *SuperAgent) IdempotencyKey() *SuperAgent {
+	s.idempotencyKey = true
+	return s
+}
+
+// newIdempotencyKey returns a random UUID version 4.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// isIdempotent reports whether the request can be sent again without changing the result.
+func (s *SuperAgent) isIdempotent() bool {
+	switch strings.ToUpper(s.Method) {
+	case GET, HEAD, OPTIONS, http.MethodTrace, PUT, DELETE:
 		return true
 	}
-	return false
+	return s.Retryable.idempotencyKey != "" || s.Header.Get(headerIdempotencyKey) != ""
+}
+
+// SetRetryPolicy sets how long to wait between the attempts of Retry, the sleep is aborted when
+// the context given to Context is done.
+// Example. To retry 5 times with an exponential backoff from 100ms up to 5s, for at most 30s,
+// and wait for the Retry-After header of 429 and 503 responses
+//
+//	gorequest.New().
+//	  Get("https://httpbin.org/get").
+//	  Retry(5, 0, http.StatusTooManyRequests, http.StatusServiceUnavailable).
+//	  SetRetryPolicy(gorequest.RetryPolicy{
+//	    Backoff:           gorequest.BackoffExponential,
+//	    BaseDelay:         100 * time.Millisecond,
+//	    MaxDelay:          5 * time.Second,
+//	    MaxElapsedTime:    30 * time.Second,
+//	    RespectRetryAfter: true,
+//	  }).
+//	  End()
+func (s *SuperAgent) SetRetryPolicy(policy RetryPolicy) *SuperAgent {
+	s.Retryable.Policy = policy
+	return s
+}
+
+// startRetry resets the retry state before the first attempt of a request.
+func (s *SuperAgent) startRetry() {
+	s.Retryable.Attempt = 0
+	s.Retryable.startTime = time.Now()
+	s.Retryable.prevDelay = 0
+	s.Retryable.idempotencyKey = ""
+	if s.idempotencyKey && s.Header.Get(headerIdempotencyKey) == "" {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			s.appendError(PhaseRequest, fmt.Errorf("generate idempotency key: %w", err))
+			return
+		}
+		s.Retryable.idempotencyKey = key
+	}
+}
+
+func (s *SuperAgent) shouldRetry(resp Response, errs []error) bool {
+	if !s.Retryable.Enable || s.Retryable.Attempt >= s.Retryable.RetryerCount {
+		return false
+	}
+	if len(errs) > 0 {
+		if !isRetryableError(errs) {
+			return false
+		}
+	} else if !statusesContains(s.Retryable.RetryableStatus, resp.StatusCode) {
+		return false
+	}
+	if !s.Retryable.NonIdempotent && !s.isIdempotent() {
+		return false
+	}
+
+	delay := s.retryDelay(resp)
+	policy := s.Retryable.Policy
+	if policy.MaxElapsedTime > 0 && time.Since(s.Retryable.startTime)+delay > policy.MaxElapsedTime {
+		return false
+	}
+	if err := s.sleep(delay); err != nil {
+		return false
+	}
+	s.Retryable.Attempt++
+	return true
+}
+
+// isRetryableError reports whether errs happened while sending the request or reading the response.
+// Build errors, open circuits and full MaxConcurrent queues are returned as is, sending the request
+// again right away won't fix them.
+func isRetryableError(errs []error) bool {
+	for _, err := range errs {
+		var e *Error
+		if errors.As(err, &e) && e.Phase != PhaseTransport && e.Phase != PhaseReadBody {
+			return false
+		}
+		if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrConcurrencyLimit) {
+			return false
+		}
+	}
+	return true
+}
+
+// retryDelay computes the delay before the next attempt.
+func (s *SuperAgent) retryDelay(resp Response) time.Duration {
+	policy := s.Retryable.Policy
+	if policy.RespectRetryAfter && resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return delay
+		}
+	}
+
+	base := policy.BaseDelay
+	if base <= 0 {
+		base = s.Retryable.RetryerTime
+	}
+
+	var delay time.Duration
+	switch policy.Backoff {
+	case BackoffExponential:
+		delay = base
+		for i := 0; i < s.Retryable.Attempt && (policy.MaxDelay <= 0 || delay < policy.MaxDelay); i++ {
+			delay *= 2
+		}
+	case BackoffDecorrelatedJitter:
+		upper := s.Retryable.prevDelay * 3
+		if upper <= base {
+			delay = base
+		} else {
+			delay = base + time.Duration(mathrand.Int63n(int64(upper-base))) //nolint:gosec // jitter doesn't need a secure random
+		}
+	default:
+		delay = base
+	}
+
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	s.Retryable.prevDelay = delay
+	return delay
+}
+
+// parseRetryAfter parses the Retry-After header, which is either delay-seconds or an HTTP-date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
+
+// sleep waits for d, it returns the context error when the context given to Context is done before.
+func (s *SuperAgent) sleep(d time.Duration) error {
+	if s.ctx == nil {
+		time.Sleep(d)
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
 }
 
 func (s *SuperAgent) getResponseBytesWithRetry() (Response, []byte, []error) {
@@ -66,9 +294,10 @@ func (s *SuperAgent) getResponseBytesWithRetry() (Response, []byte, []error) {
 		body []byte
 	)
 
+	s.startRetry()
 	for {
 		resp, body, errs = s.getResponseBytes()
-		if !s.shouldRetry(resp, len(errs) > 0) {
+		if !s.shouldRetry(resp, errs) {
 			s.setRetryCountHeader(resp)
 			break
 		}
@@ -76,9 +305,52 @@ func (s *SuperAgent) getResponseBytesWithRetry() (Response, []byte, []error) {
 		s.Errors = nil
 	}
 
+	if errs == nil {
+		if err := s.checkStatus(resp, body); err != nil {
+			s.Errors = append(s.Errors, err)
+			errs = s.Errors
+		}
+	}
+
 	return resp, body, errs
 }
 
+func (s *SuperAgent) getResponseWithRetry() (Response, []error) {
+	var (
+		errs []error
+		resp Response
+	)
+
+	s.startRetry()
+	for {
+		resp, errs = s.getResponse(false)
+		if !s.shouldRetry(resp, errs) {
+			s.setRetryCountHeader(resp)
+			break
+		}
+
+		if resp != nil {
+			discardBody(resp)
+		}
+		s.Errors = nil
+	}
+
+	if errs == nil {
+		if err := s.checkStreamStatus(resp); err != nil {
+			s.Errors = append(s.Errors, err)
+			errs = s.Errors
+		}
+	}
+
+	return resp, errs
+}
+
+// discardBody drains a bit of the body so the connection can be reused, then closes it.
+func discardBody(resp Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+}
+
 func (s *SuperAgent) setRetryCountHeader(resp Response) {
 	if resp != nil {
 		resp.Header.Set("Retry-Count", strconv.Itoa(s.Retryable.Attempt))
@@ -90,5 +362,6 @@ func copyRetryable(old superAgentRetryable) superAgentRetryable {
 	newRetryable := old
 	newRetryable.RetryableStatus = make([]int, len(old.RetryableStatus))
 	copy(newRetryable.RetryableStatus, old.RetryableStatus)
+	newRetryable.idempotencyKey = ""
 	return newRetryable
 }
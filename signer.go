@@ -0,0 +1,77 @@
+package gorequest
+
+import (
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+)
+
+// requestSigner signs requests right before they are sent, see SignAWSv4 and SignMessage. The
+// signature covers the url and the date, so it's called again for every attempt and every redirect.
+type requestSigner interface {
+	// check returns an error when req can't be signed, it's called by MakeRequest so the request
+	// fails before being sent.
+	check(req *http.Request) error
+	// sign signs req, getBody returns its body from the start to hash it, it may be nil.
+	sign(req *http.Request, getBody func() (io.ReadCloser, error)) error
+}
+
+// clientDo returns the Handler sending the requests with the http.Client. The requests and the
+// requests following the redirects are signed by signer when it's not nil, their body is wrapped
+// for the upload progress after that so hashing it isn't reported as uploaded.
+func (s *SuperAgent) clientDo(signer requestSigner) Handler {
+	if signer == nil {
+		return func(req *http.Request) (*http.Response, error) {
+			return s.Client.Do(s.wrapUploadProgress(req))
+		}
+	}
+	return func(req *http.Request) (*http.Response, error) {
+		getBody := req.GetBody
+		if err := signer.sign(req, getBody); err != nil {
+			return nil, err
+		}
+		client := *s.Client
+		checkRedirect := client.CheckRedirect
+		client.CheckRedirect = func(redirect *http.Request, via []*http.Request) error {
+			if checkRedirect != nil {
+				if err := checkRedirect(redirect, via); err != nil {
+					return err
+				}
+			} else if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return signer.sign(redirect, getBody)
+		}
+		return client.Do(s.wrapUploadProgress(req))
+	}
+}
+
+// isStreamBody reports whether body is streamed from a reader or a file, see SendReader.
+func isStreamBody(body io.ReadCloser) bool {
+	if progress, ok := body.(*progressReadCloser); ok {
+		body = progress.ReadCloser
+	}
+	switch body.(type) {
+	case *streamBody, *multiReadCloser:
+		return true
+	}
+	return false
+}
+
+// hashBody writes the request body to h without consuming it, from getBody.
+func hashBody(req *http.Request, getBody func() (io.ReadCloser, error), h hash.Hash) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if getBody == nil {
+		return errors.New("request body can't be read again to be hashed")
+	}
+	body, err := getBody()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(h, body)
+	return err
+}
@@ -0,0 +1,278 @@
+package gorequest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	awsV4Algorithm      = "AWS4-HMAC-SHA256"
+	awsV4TimeFormat     = "20060102T150405Z"
+	awsUnsignedPayload  = "UNSIGNED-PAYLOAD"
+	awsMaxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// awsUnsignedHeaders are not signed, they may be changed or added on the way to the server.
+var awsUnsignedHeaders = map[string]bool{
+	"authorization":     true,
+	"user-agent":        true,
+	"expect":            true,
+	"cookie":            true,
+	"connection":        true,
+	"te":                true,
+	"transfer-encoding": true,
+	"upgrade":           true,
+	"x-amzn-trace-id":   true,
+}
+
+// AWSCredentials are the credentials of AWS Signature Version 4.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is the token of temporary credentials, sent in X-Amz-Security-Token.
+	SessionToken string
+}
+
+// SignAWSv4 signs the requests with AWS Signature Version 4, for AWS and S3 compatible services.
+// The request is signed right before it's sent, so again on every attempt and redirect, and the
+// signature covers the headers set on the SuperAgent, the query and the payload. A body streamed
+// with SendReader or a file is sent with an UNSIGNED-PAYLOAD hash, X-Amz-Content-Sha256 is set for
+// S3 and for unsigned payloads.
+// Example. To upload an object to MinIO
+//
+//	creds := gorequest.AWSCredentials{AccessKeyID: "minioadmin", SecretAccessKey: "minioadmin"}
+//	gorequest.New().
+//	  Put("http://localhost:9000/bucket/report.csv").
+//	  SignAWSv4(creds, "us-east-1", "s3").
+//	  Type("text").
+//	  Send(report).
+//	  End()
+func (s *SuperAgent) SignAWSv4(creds AWSCredentials, region string, service string) *SuperAgent {
+	s.signer = &awsV4Signer{creds: creds, region: region, service: service, now: time.Now}
+	return s
+}
+
+// PresignAWSv4 returns the url of the request with an AWS Signature Version 4 in its query, valid
+// for expires, at most 7 days. Only the host header is signed and the payload is not, so anyone
+// with the url can send the request, an S3 GET or PUT for instance.
+//
+//	link, err := gorequest.New().
+//	  Get("https://bucket.s3.amazonaws.com/report.csv").
+//	  PresignAWSv4(creds, "us-east-1", "s3", 15*time.Minute)
+func (s *SuperAgent) PresignAWSv4(creds AWSCredentials, region string, service string, expires time.Duration) (string, error) {
+	if len(s.Errors) != 0 {
+		return "", joinErrors(s.Errors)
+	}
+	if expires <= 0 || expires > awsMaxPresignExpiry {
+		return "", fmt.Errorf("presign expiry %s must be between 1s and %s", expires, awsMaxPresignExpiry)
+	}
+	req, err := s.MakeRequest()
+	if err != nil {
+		return "", err
+	}
+	if body, ok := req.Body.(*streamBody); ok {
+		body.Close()
+	}
+	signer := &awsV4Signer{creds: creds, region: region, service: service, now: time.Now}
+	return signer.presign(req, expires), nil
+}
+
+// awsV4Signer is the requestSigner of SignAWSv4.
+type awsV4Signer struct {
+	creds   AWSCredentials
+	region  string
+	service string
+	now     func() time.Time
+}
+
+// check accepts every request, a body which can't be read again is sent as UNSIGNED-PAYLOAD.
+func (a *awsV4Signer) check(*http.Request) error {
+	return nil
+}
+
+func (a *awsV4Signer) sign(req *http.Request, getBody func() (io.ReadCloser, error)) error {
+	t := a.now().UTC()
+	req.Header.Del("Authorization")
+	req.Header.Set("X-Amz-Date", t.Format(awsV4TimeFormat))
+	if a.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.creds.SessionToken)
+	} else {
+		req.Header.Del("X-Amz-Security-Token")
+	}
+
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		if isStreamBody(req.Body) {
+			payloadHash = awsUnsignedPayload
+		} else {
+			h := sha256.New()
+			if err := hashBody(req, getBody, h); err != nil {
+				return err
+			}
+			payloadHash = hex.EncodeToString(h.Sum(nil))
+		}
+		if a.service == "s3" || payloadHash == awsUnsignedPayload {
+			req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+		}
+	}
+
+	headers := a.canonicalHeaders(req)
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	signedHeaders := strings.Join(names, ";")
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signature := a.signature(t, strings.Join([]string{
+		req.Method,
+		a.canonicalURI(req.URL),
+		a.canonicalQuery(req.URL),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsV4Algorithm, a.creds.AccessKeyID, a.scope(t), signedHeaders, signature))
+	return nil
+}
+
+func (a *awsV4Signer) presign(req *http.Request, expires time.Duration) string {
+	t := a.now().UTC()
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", awsV4Algorithm)
+	query.Set("X-Amz-Credential", a.creds.AccessKeyID+"/"+a.scope(t))
+	query.Set("X-Amz-Date", t.Format(awsV4TimeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if a.creds.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", a.creds.SessionToken)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	payloadHash := sha256Hex(nil)
+	if a.service == "s3" {
+		payloadHash = awsUnsignedPayload
+	}
+	signature := a.signature(t, strings.Join([]string{
+		req.Method,
+		a.canonicalURI(req.URL),
+		a.canonicalQuery(req.URL),
+		"host:" + requestHost(req) + "\n",
+		"host",
+		payloadHash,
+	}, "\n"))
+	req.URL.RawQuery += "&X-Amz-Signature=" + signature
+	return req.URL.String()
+}
+
+func (a *awsV4Signer) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + a.region + "/" + a.service + "/aws4_request"
+}
+
+func (a *awsV4Signer) signature(t time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		awsV4Algorithm,
+		t.Format(awsV4TimeFormat),
+		a.scope(t),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	key := hmacSHA256([]byte("AWS4"+a.creds.SecretAccessKey), t.Format("20060102"))
+	key = hmacSHA256(key, a.region)
+	key = hmacSHA256(key, a.service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalHeaders returns the signed headers by lower case name.
+func (a *awsV4Signer) canonicalHeaders(req *http.Request) map[string]string {
+	headers := map[string]string{"host": requestHost(req)}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if awsUnsignedHeaders[name] || name == "host" {
+			continue
+		}
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[name] = strings.Join(trimmed, ",")
+	}
+	return headers
+}
+
+// canonicalURI returns the encoded path, S3 encodes it once and the other services twice.
+// The path sent is set to the same encoding, so the server computes the same canonical path.
+func (a *awsV4Signer) canonicalURI(u *url.URL) string {
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	encoded := awsURIEncode(path, false)
+	u.RawPath = encoded
+	if a.service == "s3" {
+		return encoded
+	}
+	return awsURIEncode(encoded, false)
+}
+
+// canonicalQuery returns the query sorted by name and value, the query sent is set to it.
+func (a *awsV4Signer) canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsURIEncode(name, true)+"="+awsURIEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	u.RawQuery = strings.Join(pairs, "&")
+	return u.RawQuery
+}
+
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+// awsURIEncode encodes everything but the unreserved characters of RFC 3986, and the slashes
+// unless encodeSlash.
+func awsURIEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
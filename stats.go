@@ -7,6 +7,14 @@ type Stats struct {
 	ResponseBytes int64
 
 	RequestDuration time.Duration
+	// RateLimitWait is how long the request waited for the rate limiter, see RateLimit.
+	RateLimitWait time.Duration
+	// QueueWait is how long the request waited for a slot of MaxConcurrent.
+	QueueWait time.Duration
+	// HedgeWinner is the number of the copy of a Hedge request whose response was used, starting from 1.
+	HedgeWinner int
+	// Cache tells how Cache answered the request, empty without Cache.
+	Cache CacheStatus
 }
 
 func copyStats(old Stats) Stats {
@@ -0,0 +1,92 @@
+package gorequest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxHTTPErrorBody is the max length of the response body kept in an HTTPError.
+const maxHTTPErrorBody = 4096
+
+// HTTPError is returned, wrapped in an *Error, when the response status code is not expected.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	// Body is the beginning of the response body, at most 4096 bytes.
+	Body []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected response status %s", e.Status)
+}
+
+// ExpectStatus makes a response whose status code is not one of codes an error,
+// the error is an *Error wrapping an *HTTPError, and EndStruct doesn't decode the body then.
+// The status is checked after retries.
+// Example. To expect `201 Created`
+//
+//	gorequest.New().
+//	  Post("https://httpbin.org/post").
+//	  ExpectStatus(http.StatusCreated).
+//	  End()
+func (s *SuperAgent) ExpectStatus(codes ...int) *SuperAgent {
+	for _, code := range codes {
+		if len(http.StatusText(code)) == 0 {
+			s.appendError(PhaseBuild, fmt.Errorf("StatusCode '%d' doesn't exist in http package", code))
+		}
+	}
+	s.expectedStatus = append([]int(nil), codes...)
+	s.expectSuccess = false
+	return s
+}
+
+// ExpectSuccess makes a response whose status code is not 2xx an error, see ExpectStatus.
+func (s *SuperAgent) ExpectSuccess() *SuperAgent {
+	s.expectedStatus = nil
+	s.expectSuccess = true
+	return s
+}
+
+func (s *SuperAgent) isExpectedStatus(code int) bool {
+	if s.expectSuccess {
+		return code >= 200 && code < 300
+	}
+	return len(s.expectedStatus) == 0 || statusesContains(s.expectedStatus, code)
+}
+
+// checkStatus returns an error when the status code of resp is not expected,
+// body is the beginning of the response body.
+func (s *SuperAgent) checkStatus(resp Response, body []byte) error {
+	if resp == nil || s.isExpectedStatus(resp.StatusCode) {
+		return nil
+	}
+	return s.httpError(resp, body)
+}
+
+// httpError returns the error of a response whose status code is not expected.
+func (s *SuperAgent) httpError(resp Response, body []byte) error {
+	if len(body) > maxHTTPErrorBody {
+		body = body[:maxHTTPErrorBody]
+	}
+	return s.newError(PhaseStatus, &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header.Clone(),
+		Body:       shallowCopyBytes(body),
+	})
+}
+
+// checkStreamStatus is checkStatus for a response whose body is not read yet,
+// the body is replaced by the beginning of it when the status code is not expected.
+func (s *SuperAgent) checkStreamStatus(resp Response) error {
+	if resp == nil || s.isExpectedStatus(resp.StatusCode) {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxHTTPErrorBody))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return s.checkStatus(resp, body)
+}
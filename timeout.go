@@ -25,7 +25,7 @@ type Timeouts struct {
 
 func (s *SuperAgent) Timeouts(timeouts *Timeouts) *SuperAgent {
 	if timeouts == nil {
-		s.Errors = append(s.Errors, errors.New("timeouts func: nil timeouts"))
+		s.appendError(PhaseBuild, errors.New("timeouts func: nil timeouts"))
 		return s
 	}
 
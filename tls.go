@@ -1,6 +1,17 @@
 package gorequest
 
-import "crypto/tls"
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
 
 // TLSClientConfig set TLSClientConfig for underling Transport.
 // One example is you can use it to disable security check (https):
@@ -11,5 +22,174 @@ import "crypto/tls"
 func (s *SuperAgent) TLSClientConfig(config *tls.Config) *SuperAgent {
 	s.safeModifyTransport()
 	s.Transport.TLSClientConfig = config
+	s.publicKeyPins = nil
 	return s
 }
+
+// modifyTLSConfig changes a copy of the TLS config of the transport, so a config given to
+// TLSClientConfig or shared with another SuperAgent is never changed.
+func (s *SuperAgent) modifyTLSConfig(modify func(config *tls.Config) error) *SuperAgent {
+	config := s.Transport.TLSClientConfig.Clone()
+	if config == nil {
+		config = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if err := modify(config); err != nil {
+		s.appendError(PhaseBuild, err)
+		return s
+	}
+	s.safeModifyTransport()
+	s.Transport.TLSClientConfig = config
+	return s
+}
+
+// ClientCertificate adds a client certificate for mutual TLS from PEM files, the key must not be encrypted.
+// Example. To authenticate with a certificate signed by the CA of the server
+//
+//	gorequest.New().
+//	  ClientCertificate("./client.crt", "./client.key").
+//	  RootCAs("./internal-ca.pem").
+//	  Get("https://internal.example.com").
+//	  End()
+func (s *SuperAgent) ClientCertificate(certPEMFile string, keyPEMFile string) *SuperAgent {
+	cert, err := tls.LoadX509KeyPair(certPEMFile, keyPEMFile)
+	if err != nil {
+		s.appendError(PhaseBuild, fmt.Errorf("client certificate: %w", err))
+		return s
+	}
+	return s.ClientTLSCertificate(cert)
+}
+
+// ClientCertificatePEM adds a client certificate for mutual TLS from PEM encoded data.
+func (s *SuperAgent) ClientCertificatePEM(certPEM []byte, keyPEM []byte) *SuperAgent {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		s.appendError(PhaseBuild, fmt.Errorf("client certificate: %w", err))
+		return s
+	}
+	return s.ClientTLSCertificate(cert)
+}
+
+// ClientTLSCertificate adds a client certificate for mutual TLS.
+func (s *SuperAgent) ClientTLSCertificate(cert tls.Certificate) *SuperAgent {
+	return s.modifyTLSConfig(func(config *tls.Config) error {
+		config.Certificates = append(append([]tls.Certificate(nil), config.Certificates...), cert)
+		return nil
+	})
+}
+
+// RootCAs adds the certificates of PEM files to the certificate authorities trusted to verify the servers,
+// they are added to the system pool, or to the pool of a previous call. See ReplaceRootCAs to only trust them.
+func (s *SuperAgent) RootCAs(pemFiles ...string) *SuperAgent {
+	return s.modifyTLSConfig(func(config *tls.Config) error {
+		pool := config.RootCAs
+		if pool == nil {
+			systemPool, err := x509.SystemCertPool()
+			if err != nil {
+				return fmt.Errorf("root CAs: %w", err)
+			}
+			pool = systemPool
+		} else {
+			pool = pool.Clone()
+		}
+		if err := appendPEMFiles(pool, pemFiles); err != nil {
+			return err
+		}
+		config.RootCAs = pool
+		return nil
+	})
+}
+
+// ReplaceRootCAs trusts only the certificate authorities of PEM files to verify the servers, see RootCAs.
+func (s *SuperAgent) ReplaceRootCAs(pemFiles ...string) *SuperAgent {
+	return s.modifyTLSConfig(func(config *tls.Config) error {
+		pool := x509.NewCertPool()
+		if err := appendPEMFiles(pool, pemFiles); err != nil {
+			return err
+		}
+		config.RootCAs = pool
+		return nil
+	})
+}
+
+func appendPEMFiles(pool *x509.CertPool, pemFiles []string) error {
+	for _, file := range pemFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("root CAs: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("root CAs: no certificate found in %s", file)
+		}
+	}
+	return nil
+}
+
+// PinPublicKey accepts a server only when a certificate of its chain has one of the pinned public keys.
+// A pin is the SHA-256 of the DER encoded SubjectPublicKeyInfo, in base64 or hex, and may have a
+// "sha256/" prefix. The check runs in VerifyConnection after the usual verification, pins of
+// successive calls are added. At least one pin is needed, an invalid pin is a build error.
+// Example. To get the pin of a server
+//
+//	openssl s_client -connect example.com:443 </dev/null | openssl x509 -pubkey -noout |
+//	  openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+func (s *SuperAgent) PinPublicKey(hashes ...string) *SuperAgent {
+	if len(hashes) == 0 {
+		s.appendError(PhaseBuild, errors.New("pinPublicKey func: no pin"))
+		return s
+	}
+	pins := &publicKeyPins{}
+	for _, pin := range hashes {
+		hash, err := decodePin(pin)
+		if err != nil {
+			s.appendError(PhaseBuild, err)
+			return s
+		}
+		pins.hashes = append(pins.hashes, hash)
+	}
+	return s.modifyTLSConfig(func(config *tls.Config) error {
+		if s.publicKeyPins != nil {
+			pins.hashes = append(append([][]byte(nil), s.publicKeyPins.hashes...), pins.hashes...)
+			pins.next = s.publicKeyPins.next
+		} else {
+			pins.next = config.VerifyConnection
+		}
+		config.VerifyConnection = pins.verify
+		s.publicKeyPins = pins
+		return nil
+	})
+}
+
+// publicKeyPins is the VerifyConnection of PinPublicKey, it's not changed once set in a TLS config.
+type publicKeyPins struct {
+	hashes [][]byte
+	// next is the VerifyConnection of the TLS config before the pins.
+	next func(tls.ConnectionState) error
+}
+
+func (p *publicKeyPins) verify(state tls.ConnectionState) error {
+	if p.next != nil {
+		if err := p.next(state); err != nil {
+			return err
+		}
+	}
+	for _, cert := range state.PeerCertificates {
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		for _, hash := range p.hashes {
+			if bytes.Equal(sum[:], hash) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no pinned public key in the certificate chain of %s", state.ServerName)
+}
+
+func decodePin(pin string) ([]byte, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(pin), "sha256/")
+	if hash, err := hex.DecodeString(value); err == nil && len(hash) == sha256.Size {
+		return hash, nil
+	}
+	if hash, err := base64.StdEncoding.DecodeString(value); err == nil && len(hash) == sha256.Size {
+		return hash, nil
+	}
+	return nil, fmt.Errorf("invalid public key pin %q, expected a base64 or hex SHA-256", pin)
+}
@@ -67,6 +67,9 @@ func shallowCopyFileArray(old []File) []File {
 	}
 	newData := make([]File, len(old))
 	copy(newData, old)
+	for i := range newData {
+		newData[i].source = newData[i].source.clone()
+	}
 	return newData
 }
 